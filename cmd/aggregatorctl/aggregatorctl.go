@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/circuitbreaker"
+	"app_aggregator/internal/config"
+	"app_aggregator/internal/pii"
+	"app_aggregator/internal/repository"
+	"app_aggregator/internal/services"
+	"app_aggregator/pkg/db"
+)
+
+const usage = `Usage: aggregatorctl [-o table|json] <resource> <command> [args]
+
+Organizations:
+  orgs list
+  orgs create <name>
+  orgs rename <uuid> <name>
+  orgs delete <uuid>
+
+Loan applications:
+  apps inspect <uuid>
+  apps reroute <uuid>
+
+Settings:
+  settings list
+  settings get <organization_uuid>
+  settings set <organization_uuid> [-new-client=bool] [-pdn=0..80] [-has-debt=bool]
+
+Phones:
+  phones encrypt
+
+Flags:
+`
+
+var errUsage = errors.New("invalid usage")
+
+type cli struct {
+	organizations   *services.OrganizationService
+	loanApplication *services.LoanApplicationService
+	settings        *services.SettingsService
+	audit           *services.AuditService
+	out             *printer
+}
+
+func main() {
+	output := flag.String("o", outputTable, "output format: table or json")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	out, err := newPrinter(os.Stdout, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// логи сервисов не должны смешиваться с выводом команды
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	cfg, err := config.InitConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize configuration:", err)
+		os.Exit(1)
+	}
+
+	database, err := db.InitDB(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize database:", err)
+		os.Exit(1)
+	}
+
+	breakerConfig := &circuitbreaker.Config{
+		FailureThreshold: cfg.CircuitBreaker.FailureThreshold,
+		CoolDown:         cfg.CircuitBreaker.CoolDown,
+		HalfOpenRequests: cfg.CircuitBreaker.HalfOpenRequests,
+	}
+	phones, err := pii.NewCipher(cfg.PII.EncryptionKeys, cfg.PII.CurrentKeyID, cfg.PII.IndexKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize phone encryption:", err)
+		os.Exit(1)
+	}
+	repo, err := repository.NewRepository(database, breakerConfig, phones, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to initialize repositories:", err)
+		os.Exit(1)
+	}
+	organizationRepo := repository.NewOrganizationRepository(repo)
+	settingsRepo := repository.NewSettingsRepository(repo)
+
+	c := &cli{
+		organizations: services.NewOrganizationService(organizationRepo),
+		loanApplication: services.NewLoanApplicationService(
+			repository.NewLoanApplicationsRepository(repo),
+			repository.NewClientRepository(repo),
+			settingsRepo,
+			organizationRepo,
+		),
+		settings: services.NewSettingsService(settingsRepo, organizationRepo),
+		audit:    services.NewAuditService(repository.NewAuditRepository(repo)),
+		out:      out,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx = auth.WithActor(ctx, cliActor())
+
+	err = c.run(ctx, flag.Args())
+	stop()
+	database.Close()
+
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "orgs", "organizations":
+		return c.runOrganizations(ctx, args[1:])
+	case "apps", "applications":
+		return c.runApplications(ctx, args[1:])
+	case "settings":
+		return c.runSettings(ctx, args[1:])
+	case "phones":
+		return c.runPhones(ctx, args[1:])
+	}
+	return fmt.Errorf("%w: unknown resource %q", errUsage, args[0])
+}
+
+// cliActor - имя, под которым изменения из CLI попадают в аудит
+func cliActor() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return "cli:" + current.Username
+	}
+	return "cli"
+}
+
+func expectArgs(args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("%w: expected %d arguments, got %d", errUsage, n, len(args))
+	}
+	return nil
+}
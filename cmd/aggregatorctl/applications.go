@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"app_aggregator/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// applicationInspection - заявка вместе с ее историей изменений
+type applicationInspection struct {
+	*domain.LoanApplication
+	Audit []*domain.AuditEntry `json:"audit"`
+}
+
+func (c *cli) runApplications(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing applications command", errUsage)
+	}
+	if err := expectArgs(args[1:], 1); err != nil {
+		return err
+	}
+	id, err := uuid.Parse(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid loan application uuid: %w", err)
+	}
+
+	switch args[0] {
+	case "inspect":
+		return c.inspectApplication(ctx, id)
+	case "reroute":
+		app, err := c.loanApplication.Reroute(ctx, id)
+		if err != nil {
+			return err
+		}
+		return c.out.printFields(app, applicationFields(app))
+	}
+	return fmt.Errorf("%w: unknown applications command %q", errUsage, args[0])
+}
+
+func (c *cli) inspectApplication(ctx context.Context, id uuid.UUID) error {
+	app, err := c.loanApplication.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	entries, err := c.audit.List(ctx, &domain.AuditFilter{
+		EntityType: domain.AuditEntityLoanApplication,
+		EntityUUID: &id,
+	})
+	if err != nil {
+		return err
+	}
+
+	fields := applicationFields(app)
+	for _, entry := range entries {
+		fields = append(fields, [2]string{
+			"audit",
+			fmt.Sprintf("%s %s by %s", formatTime(entry.CreatedAt), entry.Action, entry.Actor),
+		})
+	}
+
+	return c.out.printFields(&applicationInspection{LoanApplication: app, Audit: entries}, fields)
+}
+
+func applicationFields(app *domain.LoanApplication) [][2]string {
+	return [][2]string{
+		{"uuid", app.UUID.String()},
+		{"status", string(app.Status)},
+		{"incoming organization", orDash(app.IncomingOrganizationName)},
+		{"issue organization", orDash(app.IssueOrganizationName)},
+		{"value", strconv.FormatInt(app.Value, 10)},
+		{"phone", app.Phone},
+		{"comment", orDash(app.Comment)},
+		{"routing reason", orDash(app.RoutingReason)},
+		{"unknown sources", orDash(strings.Join(app.UnknownSources, ", "))},
+		{"created at", formatTime(app.CreatedAt)},
+		{"updated at", formatTime(app.UpdatedAt)},
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"app_aggregator/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (c *cli) runOrganizations(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing organizations command", errUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		organizations, err := c.organizations.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printOrganizations(organizations)
+	case "create":
+		if err := expectArgs(args[1:], 1); err != nil {
+			return err
+		}
+		organization, err := c.organizations.Create(ctx, &domain.Organization{Name: args[1]})
+		if err != nil {
+			return err
+		}
+		return c.printOrganizations([]*domain.Organization{organization})
+	case "rename":
+		if err := expectArgs(args[1:], 2); err != nil {
+			return err
+		}
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid organization uuid: %w", err)
+		}
+		organization, err := c.organizations.Update(ctx, id, &domain.Organization{Name: args[2]})
+		if err != nil {
+			return err
+		}
+		return c.printOrganizations([]*domain.Organization{organization})
+	case "delete":
+		if err := expectArgs(args[1:], 1); err != nil {
+			return err
+		}
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid organization uuid: %w", err)
+		}
+		return c.organizations.Delete(ctx, id)
+	}
+	return fmt.Errorf("%w: unknown organizations command %q", errUsage, args[0])
+}
+
+func (c *cli) printOrganizations(organizations []*domain.Organization) error {
+	rows := make([][]string, len(organizations))
+	for i, organization := range organizations {
+		id := "-"
+		if organization.UUID != nil {
+			id = organization.UUID.String()
+		}
+		countries := "*"
+		if len(organization.AllowedPhoneCountries) > 0 {
+			countries = strings.Join(organization.AllowedPhoneCountries, ",")
+		}
+		rows[i] = []string{id, organization.Name, countries, formatTime(organization.CreatedAt)}
+	}
+	return c.out.print(organizations, []string{"UUID", "NAME", "PHONE COUNTRIES", "CREATED AT"}, rows)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer выводит результат команды таблицей или JSON
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON:
+		return &printer{w: w, format: format}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+// print выводит value как JSON, либо таблицу с заголовком header и строками rows
+func (p *printer) print(value interface{}, header []string, rows [][]string) error {
+	if p.format == outputJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// printFields выводит value как JSON, либо пары "поле: значение"
+func (p *printer) printFields(value interface{}, fields [][2]string) error {
+	if p.format == outputJSON {
+		return p.print(value, nil, nil)
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", field[0], field[1])
+	}
+	return w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+func (c *cli) runPhones(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing phones command", errUsage)
+	}
+
+	switch args[0] {
+	case "encrypt":
+		if err := expectArgs(args[1:], 0); err != nil {
+			return err
+		}
+		encrypted, err := c.loanApplication.EncryptPhones(ctx)
+		if err != nil {
+			return fmt.Errorf("encrypted %d loan applications before error: %w", encrypted, err)
+		}
+		result := struct {
+			Encrypted int `json:"encrypted"`
+		}{encrypted}
+		return c.out.printFields(result, [][2]string{{"encrypted", strconv.Itoa(encrypted)}})
+	}
+	return fmt.Errorf("%w: unknown phones command %q", errUsage, args[0])
+}
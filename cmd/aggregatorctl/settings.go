@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func (c *cli) runSettings(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing settings command", errUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		settings, err := c.settings.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printSettings(settings)
+	case "get":
+		if err := expectArgs(args[1:], 1); err != nil {
+			return err
+		}
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid organization uuid: %w", err)
+		}
+		settings, err := c.settings.GetByOrganization(ctx, id)
+		if err != nil {
+			return err
+		}
+		return c.printSettings([]*domain.Settings{settings})
+	case "set":
+		return c.setSettings(ctx, args[1:])
+	}
+	return fmt.Errorf("%w: unknown settings command %q", errUsage, args[0])
+}
+
+// setSettings меняет только переданные флаги, остальные значения остаются прежними
+func (c *cli) setSettings(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing organization uuid", errUsage)
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid organization uuid: %w", err)
+	}
+
+	settings, err := c.settings.GetByOrganization(ctx, id)
+	if errors.Is(err, internal.ErrRecordNoFound) {
+		settings = &domain.Settings{OrganizationUUID: id}
+	} else if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("settings set", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&settings.NewClient, "new-client", settings.NewClient, "accept new clients")
+	flags.Int64Var(&settings.PDN, "pdn", settings.PDN, "maximum client PDN, 0 disables the check")
+	flags.BoolVar(&settings.HasDebt, "has-debt", settings.HasDebt, "accept clients with active loans")
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, flags.Arg(0))
+	}
+
+	saved, err := c.settings.Save(ctx, settings)
+	if err != nil {
+		return err
+	}
+	return c.printSettings([]*domain.Settings{saved})
+}
+
+func (c *cli) printSettings(settings []*domain.Settings) error {
+	rows := make([][]string, len(settings))
+	for i, s := range settings {
+		rows[i] = []string{
+			s.OrganizationUUID.String(),
+			s.OrganizationName,
+			strconv.FormatBool(s.NewClient),
+			strconv.FormatInt(s.PDN, 10),
+			strconv.FormatBool(s.HasDebt),
+			strconv.Itoa(s.Version),
+			formatTime(s.UpdatedAt),
+		}
+	}
+	return c.out.print(settings, []string{"ORGANIZATION", "NAME", "NEW CLIENT", "PDN", "HAS DEBT", "VERSION", "UPDATED AT"}, rows)
+}
@@ -8,10 +8,13 @@ import (
 	"sync"
 	"time"
 
+	"app_aggregator/internal/circuitbreaker"
 	"app_aggregator/internal/config"
+	"app_aggregator/internal/pii"
 	"app_aggregator/internal/repository"
 	"app_aggregator/internal/router"
 	"app_aggregator/internal/services"
+	"app_aggregator/internal/tracing"
 	"app_aggregator/migrations"
 	"app_aggregator/pkg/closer"
 	"app_aggregator/pkg/db"
@@ -31,12 +34,28 @@ func main() {
 	}
 	logger.Info("Configuration initialized successfully")
 
-	logger.Info("Running database migrations")
-	if err := migrations.Up(cfg); err != nil {
-		logger.Error("Failed to run migrations", slog.String("error", err.Error()))
+	logger.Info("Initializing tracing", slog.String("exporter", cfg.Tracing.Exporter))
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing)
+	if err != nil {
+		logger.Error("Failed to initialize tracing", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
-	logger.Info("Database migrations completed")
+	closer.Add(func() error {
+		logger.Info("Flushing traces")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+
+	if cfg.MigrateOnStart {
+		logger.Info("Running database migrations")
+		applied, err := migrations.Up(context.Background(), cfg)
+		if err != nil {
+			logger.Error("Failed to run migrations", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		logger.Info("Database migrations completed", slog.Int("applied", len(applied)))
+	}
 
 	logger.Info("Initializing database connection")
 	database, err := db.InitDB(cfg)
@@ -47,16 +66,109 @@ func main() {
 	logger.Info("Database connection established")
 
 	logger.Info("Initializing repositories")
-	repo := repository.NewRepository(database)
+	breakerConfig := &circuitbreaker.Config{
+		FailureThreshold: cfg.CircuitBreaker.FailureThreshold,
+		CoolDown:         cfg.CircuitBreaker.CoolDown,
+		HalfOpenRequests: cfg.CircuitBreaker.HalfOpenRequests,
+	}
+	phones, err := pii.NewCipher(cfg.PII.EncryptionKeys, cfg.PII.CurrentKeyID, cfg.PII.IndexKey)
+	if err != nil {
+		logger.Error("Failed to initialize phone encryption", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	repo, err := repository.NewRepository(database, breakerConfig, phones, logger)
+	if err != nil {
+		logger.Error("Failed to initialize repositories", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 	organizationRepo := repository.NewOrganizationRepository(repo)
 	loanApplicationRepo := repository.NewLoanApplicationsRepository(repo)
+	settingsRepo := repository.NewSettingsRepository(repo)
+	clientRepo := repository.NewClientRepository(repo)
+	auditRepo := repository.NewAuditRepository(repo)
+	apiKeyRepo := repository.NewAPIKeyRepository(repo)
+	operatorRepo := repository.NewOperatorRepository(repo)
+	idempotencyRepo := repository.NewIdempotencyRepository(repo)
+	dedupRepo := repository.NewDedupRepository(repo)
+	webhookRepo := repository.NewWebhookRepository(repo)
+	importRepo := repository.NewImportRepository(repo)
 
 	logger.Info("Initializing services")
 	organizationService := services.NewOrganizationService(organizationRepo)
-	loanApplicationService := services.NewLoanApplicationService(loanApplicationRepo)
+	settingsService := services.NewSettingsService(settingsRepo, organizationRepo)
+	loanApplicationService := services.NewLoanApplicationService(loanApplicationRepo, clientRepo, settingsRepo, organizationRepo)
+	clientService := services.NewClientService(clientRepo)
+	auditService := services.NewAuditService(auditRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, organizationRepo)
+	operatorService := services.NewOperatorService(operatorRepo, cfg.Auth.TokenTTL)
+
+	dedupService := services.NewDedupService(dedupRepo, organizationRepo)
+	webhookService := services.NewWebhookService(webhookRepo, organizationRepo)
+	importService := services.NewImportService(importRepo, organizationRepo, loanApplicationService,
+		cfg.Import.MaxRows, cfg.Import.SyncRows, logger)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.Retention)
+	healthService := services.NewHealthService(healthDependencies(cfg, database), cfg.Health.Timeout)
+
+	if failed, err := importService.FailInterrupted(context.Background()); err != nil {
+		logger.Error("Failed to mark interrupted import jobs", slog.String("error", err.Error()))
+	} else if failed > 0 {
+		logger.Warn("Interrupted import jobs marked as failed", slog.Int64("count", failed))
+	}
+
+	if cfg.Auth.AdminLogin != "" {
+		if err := operatorService.EnsureAdmin(context.Background(), cfg.Auth.AdminLogin, cfg.Auth.AdminPassword); err != nil {
+			logger.Error("Failed to create admin operator", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
 
 	logger.Info("Initializing HTTP server")
-	httpServer := router.NewHTTPServer(organizationService, loanApplicationService, logger)
+	httpServer := router.NewHTTPServer(&router.Services{
+		Organization:    organizationService,
+		Settings:        settingsService,
+		LoanApplication: loanApplicationService,
+		Client:          clientService,
+		Audit:           auditService,
+		APIKey:          apiKeyService,
+		Operator:        operatorService,
+		Health:          healthService,
+		Idempotency:     idempotencyService,
+		Dedup:           dedupService,
+		Webhook:         webhookService,
+		Import:          importService,
+	}, logger)
+
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	go idempotencyService.RunCleanup(cleanupCtx, cfg.Idempotency.CleanupInterval, logger)
+	closer.Add(func() error {
+		stopCleanup()
+		return nil
+	})
+
+	// Телефоны, сохраненные до шифрования или старым ключом, перешифровываются в фоне
+	encryptCtx, stopEncrypt := context.WithCancel(context.Background())
+	go func() {
+		encrypted, err := loanApplicationService.EncryptPhones(encryptCtx)
+		if err != nil {
+			logger.Error("Failed to encrypt loan application phones", slog.Int("encrypted", encrypted), slog.String("error", err.Error()))
+			return
+		}
+		if encrypted > 0 {
+			logger.Info("Loan application phones encrypted", slog.Int("count", encrypted))
+		}
+	}()
+	closer.Add(func() error {
+		stopEncrypt()
+		return nil
+	})
+
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo, phones, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts, cfg.Webhook.BatchSize, logger)
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	go webhookDispatcher.Run(dispatchCtx, cfg.Webhook.DispatchInterval)
+	closer.Add(func() error {
+		stopDispatch()
+		return nil
+	})
 
 	serverShutdown := make(chan struct{})
 	var shutdownOnce sync.Once
@@ -80,6 +192,18 @@ func main() {
 		return err
 	})
 
+	metricsServer := router.NewMetricsServer(cfg.MetricsAddr, logger)
+	go func() {
+		if err := metricsServer.Start(); err != nil && err != http.ErrServerClosed {
+			logger.Error("Metrics server error", slog.String("error", err.Error()))
+		}
+	}()
+	closer.Add(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return metricsServer.Shutdown(ctx)
+	})
+
 	closer.Add(func() error {
 		logger.Info("Closing database connections")
 		return database.Close()
@@ -102,6 +226,29 @@ func main() {
 	logger.Info("Application shutdown completed")
 }
 
+// healthDependencies - Postgres обязателен всегда, legacy базы - только если так настроено,
+// так как прием заявок продолжает работать без них
+func healthDependencies(cfg *config.Config, database *db.DB) []services.HealthDependency {
+	dependencies := []services.HealthDependency{{
+		Name:     db.PGSourceName,
+		Required: true,
+		Ping: func(ctx context.Context) error {
+			return db.Ping(ctx, database.PGDB)
+		},
+	}}
+	for _, source := range database.ClientSources {
+		sourceDB := source.DB
+		dependencies = append(dependencies, services.HealthDependency{
+			Name:     source.Name,
+			Required: cfg.Health.RequireClientSources,
+			Ping: func(ctx context.Context) error {
+				return db.Ping(ctx, sourceDB)
+			},
+		})
+	}
+	return dependencies
+}
+
 func initLogger() *slog.Logger {
 	handler := slog.NewJSONHandler(os.Stdout, nil)
 	logger := slog.New(handler)
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"app_aggregator/internal/config"
+	"app_aggregator/migrations"
+)
+
+const usage = `Usage: migrate <command>
+
+Commands:
+  up        apply all pending migrations
+  down [n]  roll back the last n migrations (default 1)
+  status    show applied and pending migrations
+  redo      roll back and re-apply the last migration
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg, err := config.InitMigrateConfig()
+	if err != nil {
+		logger.Error("Failed to initialize configuration", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	db, err := migrations.Open(cfg)
+	if err != nil {
+		logger.Error("Failed to connect to database", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		logger.Error("Failed to load migrations", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, migrator, flag.Args(), logger); err != nil {
+		logger.Error("Migration command failed", slog.String("command", flag.Arg(0)), slog.String("error", err.Error()))
+		stop()
+		db.Close()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *migrations.Migrator, args []string, logger *slog.Logger) error {
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			logMigration(logger, "Migration applied", migration)
+		}
+		if err != nil {
+			return err
+		}
+		logger.Info("Migrations completed", slog.Int("applied", len(applied)))
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, migration := range rolledBack {
+			logMigration(logger, "Migration rolled back", migration)
+		}
+		return err
+	case "redo":
+		migration, err := migrator.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		logMigration(logger, "Migration redone", migration)
+		return nil
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+		return nil
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+func logMigration(logger *slog.Logger, msg string, migration *migrations.Migration) {
+	logger.Info(msg, slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+}
+
+func printStatus(statuses []*migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Dirty {
+			state = "checksum mismatch"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// BearerToken извлекает токен из заголовка Authorization: Bearer <token>
+func BearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && token != ""
+}
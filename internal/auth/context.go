@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+
+	"app_aggregator/internal/domain"
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	organizationKey
+	operatorKey
+)
+
+const AnonymousActor = "anonymous"
+
+// WithActor сохраняет в контексте того, кто выполняет изменение (для аудита)
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// WithOrganization сохраняет в контексте организацию-партнера, аутентифицированную по API ключу
+func WithOrganization(ctx context.Context, organization *domain.Organization) context.Context {
+	return context.WithValue(ctx, organizationKey, organization)
+}
+
+func OrganizationFromContext(ctx context.Context) (*domain.Organization, bool) {
+	organization, ok := ctx.Value(organizationKey).(*domain.Organization)
+	return organization, ok && organization != nil
+}
+
+// WithOperator сохраняет в контексте оператора, аутентифицированного по токену
+func WithOperator(ctx context.Context, operator *domain.Operator) context.Context {
+	return context.WithValue(ctx, operatorKey, operator)
+}
+
+func OperatorFromContext(ctx context.Context) (*domain.Operator, bool) {
+	operator, ok := ctx.Value(operatorKey).(*domain.Operator)
+	return operator, ok && operator != nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+
+	"app_aggregator/internal/domain"
+)
+
+type Permission string
+
+const (
+	PermissionLoanApplicationsRead   Permission = "loan_applications:read"
+	PermissionLoanApplicationsWrite  Permission = "loan_applications:write"
+	PermissionLoanApplicationsDelete Permission = "loan_applications:delete"
+	PermissionLoanApplicationsImport Permission = "loan_applications:import"
+	PermissionLoanApplicationsExport Permission = "loan_applications:export"
+	PermissionOrganizationsWrite     Permission = "organizations:write"
+	PermissionSettingsRead           Permission = "settings:read"
+	PermissionClientsRead            Permission = "clients:read"
+	PermissionSourcesRead            Permission = "sources:read"
+	PermissionAuditRead              Permission = "audit:read"
+	PermissionAPIKeysManage          Permission = "api_keys:manage"
+	PermissionOperatorsManage        Permission = "operators:manage"
+	PermissionWebhooksManage         Permission = "webhooks:manage"
+	PermissionPIIRead                Permission = "pii:read"
+)
+
+var rolePermissions = map[domain.OperatorRole][]Permission{
+	domain.OperatorRoleViewer: {
+		PermissionLoanApplicationsRead,
+		PermissionClientsRead,
+		PermissionSourcesRead,
+		PermissionSettingsRead,
+	},
+	domain.OperatorRoleOperator: {
+		PermissionLoanApplicationsRead,
+		PermissionLoanApplicationsWrite,
+		PermissionLoanApplicationsExport,
+		PermissionClientsRead,
+		PermissionSourcesRead,
+		PermissionSettingsRead,
+		PermissionPIIRead,
+	},
+	domain.OperatorRoleAdmin: {
+		PermissionLoanApplicationsRead,
+		PermissionLoanApplicationsWrite,
+		PermissionLoanApplicationsDelete,
+		PermissionLoanApplicationsImport,
+		PermissionLoanApplicationsExport,
+		PermissionOrganizationsWrite,
+		PermissionSettingsRead,
+		PermissionClientsRead,
+		PermissionSourcesRead,
+		PermissionAuditRead,
+		PermissionAPIKeysManage,
+		PermissionOperatorsManage,
+		PermissionWebhooksManage,
+		PermissionPIIRead,
+	},
+}
+
+func HasPermission(role domain.OperatorRole, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanFromContext проверяет право оператора из контекста. У партнеров с API ключом прав операторов нет.
+func CanFromContext(ctx context.Context, permission Permission) bool {
+	operator, ok := OperatorFromContext(ctx)
+	return ok && HasPermission(operator.Role, permission)
+}
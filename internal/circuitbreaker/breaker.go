@@ -0,0 +1,210 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrOpen = errors.New("circuit breaker is open")
+
+// ignoredError - результат вызова, который ничего не говорит о состоянии защищаемого ресурса
+type ignoredError struct {
+	err error
+}
+
+func (e *ignoredError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ignoredError) Unwrap() error {
+	return e.err
+}
+
+// Ignore помечает ошибку, которую breaker не должен учитывать, например отмену запроса вызывающей стороной.
+// Такой вызов освобождает пробный слот half-open, но не считается ни успехом, ни отказом.
+// Execute возвращает исходную ошибку.
+func Ignore(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ignoredError{err: err}
+}
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type Stats struct {
+	State               string
+	ConsecutiveFailures int
+	TotalSuccesses      int
+	TotalFailures       int
+	Rejected            int
+	LastError           string
+	LastSuccessAt       time.Time
+	LastFailureAt       time.Time
+	OpenedAt            time.Time
+}
+
+// Breaker - circuit breaker с состояниями closed, open и half-open.
+// После FailureThreshold ошибок подряд breaker открывается на CoolDown,
+// затем пропускает HalfOpenRequests пробных запросов.
+// onStateChange вызывается под блокировкой и не должен обращаться к breaker.
+type Breaker struct {
+	name          string
+	config        *Config
+	onStateChange func(name string, from, to State)
+
+	mu               sync.Mutex
+	state            State
+	halfOpenInFlight int
+	stats            Stats
+}
+
+func NewBreaker(name string, config *Config, onStateChange func(name string, from, to State)) *Breaker {
+	return &Breaker{
+		name:          name,
+		config:        config,
+		onStateChange: onStateChange,
+		state:         StateClosed,
+	}
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+func (b *Breaker) Execute(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	var ignored *ignoredError
+	if errors.As(err, &ignored) {
+		b.release()
+		return ignored.err
+	}
+	b.record(err)
+	return err
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(time.Now())
+	return b.state
+}
+
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(time.Now())
+	stats := b.stats
+	stats.State = b.state.String()
+	return stats
+}
+
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh(time.Now())
+	switch b.state {
+	case StateOpen:
+		b.stats.Rejected++
+		return ErrOpen
+	case StateHalfOpen:
+		if b.halfOpenInFlight >= b.config.HalfOpenRequests {
+			b.stats.Rejected++
+			return ErrOpen
+		}
+		b.halfOpenInFlight++
+	}
+	return nil
+}
+
+// release освобождает пробный слот без записи результата
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.state == StateHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+
+	if err == nil {
+		b.stats.TotalSuccesses++
+		b.stats.ConsecutiveFailures = 0
+		b.stats.LastSuccessAt = now
+		if b.state == StateHalfOpen {
+			b.setState(StateClosed, now)
+		}
+		return
+	}
+
+	b.stats.TotalFailures++
+	b.stats.ConsecutiveFailures++
+	b.stats.LastError = err.Error()
+	b.stats.LastFailureAt = now
+
+	switch {
+	case b.state == StateHalfOpen:
+		b.setState(StateOpen, now)
+	case b.state == StateClosed && b.stats.ConsecutiveFailures >= b.config.FailureThreshold:
+		b.setState(StateOpen, now)
+	}
+}
+
+func (b *Breaker) refresh(now time.Time) {
+	if b.state == StateOpen && now.Sub(b.stats.OpenedAt) >= b.config.CoolDown {
+		b.setState(StateHalfOpen, now)
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+	b.halfOpenInFlight = 0
+	if state == StateOpen {
+		b.stats.OpenedAt = now
+	}
+
+	if b.onStateChange != nil {
+		b.onStateChange(b.name, from, state)
+	}
+}
@@ -0,0 +1,141 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errBackend = errors.New("backend failed")
+
+func newTestBreaker() *Breaker {
+	return NewBreaker("test", &Config{
+		FailureThreshold: 2,
+		CoolDown:         10 * time.Millisecond,
+		HalfOpenRequests: 1,
+	}, nil)
+}
+
+func openBreaker(t *testing.T, b *Breaker) {
+	t.Helper()
+	for range b.config.FailureThreshold {
+		_ = b.Execute(func() error { return errBackend })
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("State() = %s, want open", b.State())
+	}
+}
+
+func halfOpenBreaker(t *testing.T, b *Breaker) {
+	t.Helper()
+	openBreaker(t, b)
+	time.Sleep(b.config.CoolDown)
+	if b.State() != StateHalfOpen {
+		t.Fatalf("State() = %s, want half-open", b.State())
+	}
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := newTestBreaker()
+
+	_ = b.Execute(func() error { return errBackend })
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return errBackend })
+	if b.State() != StateClosed {
+		t.Fatalf("State() = %s, want closed: success must reset failures", b.State())
+	}
+
+	_ = b.Execute(func() error { return errBackend })
+	if b.State() != StateOpen {
+		t.Errorf("State() = %s, want open", b.State())
+	}
+}
+
+func TestBreakerHalfOpenResult(t *testing.T) {
+	b := newTestBreaker()
+	halfOpenBreaker(t, b)
+	_ = b.Execute(func() error { return nil })
+	if b.State() != StateClosed {
+		t.Errorf("State() after probe success = %s, want closed", b.State())
+	}
+
+	b = newTestBreaker()
+	halfOpenBreaker(t, b)
+	_ = b.Execute(func() error { return errBackend })
+	if b.State() != StateOpen {
+		t.Errorf("State() after probe failure = %s, want open", b.State())
+	}
+}
+
+func TestBreakerOpenRejects(t *testing.T) {
+	b := newTestBreaker()
+	openBreaker(t, b)
+
+	called := false
+	err := b.Execute(func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrOpen) {
+		t.Errorf("Execute() error = %v, want ErrOpen", err)
+	}
+	if called {
+		t.Error("Execute() called fn while open")
+	}
+	if stats := b.Stats(); stats.Rejected != 1 || stats.TotalFailures != 2 {
+		t.Errorf("Stats() = %+v, want 1 rejected and 2 failures", stats)
+	}
+}
+
+func TestBreakerHalfOpenLimit(t *testing.T) {
+	b := newTestBreaker()
+	halfOpenBreaker(t, b)
+
+	inside := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- b.Execute(func() error {
+			close(inside)
+			<-finish
+			return nil
+		})
+	}()
+	<-inside
+
+	if err := b.Execute(func() error { return nil }); !errors.Is(err, ErrOpen) {
+		t.Errorf("Execute() over half-open limit error = %v, want ErrOpen", err)
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Errorf("Execute() probe error = %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Errorf("State() = %s, want closed", b.State())
+	}
+}
+
+func TestBreakerIgnore(t *testing.T) {
+	b := newTestBreaker()
+	halfOpenBreaker(t, b)
+
+	err := b.Execute(func() error { return Ignore(context.Canceled) })
+	if err != context.Canceled {
+		t.Errorf("Execute() error = %v, want original context.Canceled", err)
+	}
+
+	// слот освобожден, следующий пробный запрос проходит
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Errorf("Execute() after ignored error = %v", err)
+	}
+	stats := b.Stats()
+	if stats.State != "closed" || stats.TotalFailures != 2 || stats.TotalSuccesses != 1 {
+		t.Errorf("Stats() = %+v, want closed with 2 failures and 1 success", stats)
+	}
+
+	if Ignore(nil) != nil {
+		t.Error("Ignore(nil) != nil")
+	}
+}
@@ -0,0 +1,17 @@
+package circuitbreaker
+
+import "time"
+
+type Config struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+	HalfOpenRequests int
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		FailureThreshold: 5,
+		CoolDown:         30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
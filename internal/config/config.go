@@ -1,10 +1,15 @@
 package config
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
 
@@ -12,15 +17,87 @@ type PGConfig struct {
 	DSN string
 }
 
-type SQLConfig struct {
-	DsnKassa   string
-	DsnDoverix string
-	DsnDe      string
+// ClientSourceConfig описывает legacy базу кредитора и организацию, к которой она привязана
+type ClientSourceConfig struct {
+	Name             string
+	DSN              string
+	OrganizationUUID uuid.UUID
+	Timeout          time.Duration
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+	HalfOpenRequests int
+}
+
+// AuthConfig - настройки аутентификации операторов.
+// AdminLogin и AdminPassword задают администратора, создаваемого при первом запуске.
+type AuthConfig struct {
+	TokenTTL      time.Duration
+	AdminLogin    string
+	AdminPassword string
+}
+
+// TracingConfig - настройки OpenTelemetry. Exporter: none, otlp, stdout или file.
+// Адрес OTLP коллектора задается стандартными переменными OTEL_EXPORTER_OTLP_*.
+type TracingConfig struct {
+	Exporter    string
+	FilePath    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// HealthConfig - настройки readiness проверки.
+// RequireClientSources делает legacy базы обязательными зависимостями.
+type HealthConfig struct {
+	Timeout              time.Duration
+	RequireClientSources bool
+}
+
+// IdempotencyConfig - сколько хранятся ответы для Idempotency-Key и как часто удаляются просроченные
+type IdempotencyConfig struct {
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+// WebhookConfig - настройки отправки событий на webhook организаций.
+// После MaxAttempts неудачных попыток доставка переходит в dead.
+type WebhookConfig struct {
+	DispatchInterval time.Duration
+	MaxAttempts      int
+	Timeout          time.Duration
+	BatchSize        int
+}
+
+// ImportConfig - ограничения загрузки заявок из файлов.
+// Файлы до SyncRows строк обрабатываются в запросе, если успевают, большие - фоновой задачей.
+type ImportConfig struct {
+	MaxRows  int
+	SyncRows int
+}
+
+// PIIConfig - ключи шифрования телефонов из PHONE_ENCRYPTION_KEYS ("id:base64,..."), новые записи
+// шифруются ключом CurrentKeyID. IndexKey - ключ HMAC слепого индекса, его смена требует перестроения индекса.
+type PIIConfig struct {
+	EncryptionKeys map[string][]byte
+	CurrentKeyID   string
+	IndexKey       []byte
 }
 
 type Config struct {
-	PGdb PGConfig
-	SQL  SQLConfig
+	PGdb           PGConfig
+	MigrateOnStart bool
+	MetricsAddr    string
+	ClientSources  []ClientSourceConfig
+	CircuitBreaker CircuitBreakerConfig
+	Auth           AuthConfig
+	Tracing        TracingConfig
+	Health         HealthConfig
+	Idempotency    IdempotencyConfig
+	Webhook        WebhookConfig
+	Import         ImportConfig
+	PII            PIIConfig
 }
 
 func buildMSSQLDSN(server, user, password, database string) string {
@@ -30,34 +107,359 @@ func buildMSSQLDSN(server, user, password, database string) string {
 	return fmt.Sprintf("server=%s;user id=%s;password=%s;database=%s;encrypt=disable;", server, user, password, database)
 }
 
+// InitMigrateConfig читает только то, что нужно командам миграций: подключение к Postgres.
+// Ключи шифрования и legacy источники для миграций не требуются.
+func InitMigrateConfig() (*Config, error) {
+	_ = godotenv.Load(".env.local", ".env")
+
+	pg, err := initPG()
+	if err != nil {
+		return nil, err
+	}
+	return &Config{PGdb: pg}, nil
+}
+
 func InitConfig() (*Config, error) {
 
 	_ = godotenv.Load(".env.local", ".env")
 
-	sqlServer := os.Getenv("SQL_SERVER")
-	sqlUser := os.Getenv("SQL_USER")
-	sqlPassword := os.Getenv("SQL_PASSWORD")
-	sqlKassaDb := os.Getenv("SQL_DB_KASSA")
-	sqlDoverixDb := os.Getenv("SQL_DB_DOVERIX")
-	sqlDeDb := os.Getenv("SQL_DB_DE")
+	pg, err := initPG()
+	if err != nil {
+		return nil, err
+	}
+
+	clientSources, err := initClientSources()
+	if err != nil {
+		return nil, err
+	}
+
+	circuitBreaker, err := initCircuitBreaker()
+	if err != nil {
+		return nil, err
+	}
 
-	kassaDSN := buildMSSQLDSN(sqlServer, sqlUser, sqlPassword, sqlKassaDb)
-	doverixDSN := buildMSSQLDSN(sqlServer, sqlUser, sqlPassword, sqlDoverixDb)
-	deDSN := buildMSSQLDSN(sqlServer, sqlUser, sqlPassword, sqlDeDb)
+	tokenTTL, err := durationEnv("AUTH_TOKEN_TTL", 12*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	migrateOnStart, err := boolEnv("MIGRATE_ON_START", false)
+	if err != nil {
+		return nil, err
+	}
+
+	tracing, err := initTracing()
+	if err != nil {
+		return nil, err
+	}
+
+	healthTimeout, err := durationEnv("READINESS_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	requireClientSources, err := boolEnv("READINESS_REQUIRE_CLIENT_SOURCES", false)
+	if err != nil {
+		return nil, err
+	}
 
-	pgDSN := os.Getenv("POSTGRES_DSN")
-	if pgDSN == "" {
-		return nil, errors.New("POSTGRES_DSN is required but not set")
+	idempotencyRetention, err := durationEnv("IDEMPOTENCY_RETENTION", 24*time.Hour)
+	if err != nil {
+		return nil, err
 	}
+	idempotencyCleanupInterval, err := durationEnv("IDEMPOTENCY_CLEANUP_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := initWebhook()
+	if err != nil {
+		return nil, err
+	}
+
+	importMaxRows, err := intEnv("IMPORT_MAX_ROWS", 100000)
+	if err != nil {
+		return nil, err
+	}
+	importSyncRows, err := intEnv("IMPORT_SYNC_ROWS", 20)
+	if err != nil {
+		return nil, err
+	}
+
+	pii, err := initPII()
+	if err != nil {
+		return nil, err
+	}
+
+	// метрики отдаются на внутреннем адресе, отдельном от API
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+
 	config := &Config{
-		PGdb: PGConfig{
-			DSN: pgDSN,
+		PGdb:           pg,
+		MigrateOnStart: migrateOnStart,
+		MetricsAddr:    metricsAddr,
+		ClientSources:  clientSources,
+		CircuitBreaker: circuitBreaker,
+		Auth: AuthConfig{
+			TokenTTL:      tokenTTL,
+			AdminLogin:    os.Getenv("ADMIN_LOGIN"),
+			AdminPassword: os.Getenv("ADMIN_PASSWORD"),
 		},
-		SQL: SQLConfig{
-			DsnKassa:   kassaDSN,
-			DsnDoverix: doverixDSN,
-			DsnDe:      deDSN,
+		Tracing: tracing,
+		Health: HealthConfig{
+			Timeout:              healthTimeout,
+			RequireClientSources: requireClientSources,
 		},
+		Idempotency: IdempotencyConfig{
+			Retention:       idempotencyRetention,
+			CleanupInterval: idempotencyCleanupInterval,
+		},
+		Webhook: webhook,
+		Import: ImportConfig{
+			MaxRows:  importMaxRows,
+			SyncRows: importSyncRows,
+		},
+		PII: pii,
 	}
 	return config, nil
 }
+
+func initPG() (PGConfig, error) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		return PGConfig{}, errors.New("POSTGRES_DSN is required but not set")
+	}
+	return PGConfig{DSN: dsn}, nil
+}
+
+// initClientSources читает список источников из CLIENT_SOURCES (через запятую).
+// Для каждого источника NAME используются переменные CLIENT_SOURCE_NAME_DSN
+// (или CLIENT_SOURCE_NAME_DB вместе с общими SQL_SERVER, SQL_USER, SQL_PASSWORD)
+// и CLIENT_SOURCE_NAME_ORGANIZATION_UUID. Таймаут запроса к источнику задается
+// CLIENT_SOURCE_NAME_TIMEOUT, по умолчанию CLIENT_SOURCE_TIMEOUT.
+func initClientSources() ([]ClientSourceConfig, error) {
+	sqlServer := os.Getenv("SQL_SERVER")
+	sqlUser := os.Getenv("SQL_USER")
+	sqlPassword := os.Getenv("SQL_PASSWORD")
+
+	defaultTimeout, err := durationEnv("CLIENT_SOURCE_TIMEOUT", 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []ClientSourceConfig
+	for _, name := range strings.Split(os.Getenv("CLIENT_SOURCES"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "CLIENT_SOURCE_" + strings.ToUpper(name) + "_"
+
+		dsn := os.Getenv(prefix + "DSN")
+		if dsn == "" {
+			dsn = buildMSSQLDSN(sqlServer, sqlUser, sqlPassword, os.Getenv(prefix+"DB"))
+		}
+		if dsn == "" {
+			return nil, fmt.Errorf("%sDSN or %sDB is required for client source %s", prefix, prefix, name)
+		}
+
+		organizationUUID, err := uuid.Parse(os.Getenv(prefix + "ORGANIZATION_UUID"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sORGANIZATION_UUID: %w", prefix, err)
+		}
+
+		timeout, err := durationEnv(prefix+"TIMEOUT", defaultTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, ClientSourceConfig{
+			Name:             name,
+			DSN:              dsn,
+			OrganizationUUID: organizationUUID,
+			Timeout:          timeout,
+		})
+	}
+	return sources, nil
+}
+
+func initCircuitBreaker() (CircuitBreakerConfig, error) {
+	failureThreshold, err := intEnv("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5)
+	if err != nil {
+		return CircuitBreakerConfig{}, err
+	}
+	coolDown, err := durationEnv("CIRCUIT_BREAKER_COOL_DOWN", 30*time.Second)
+	if err != nil {
+		return CircuitBreakerConfig{}, err
+	}
+	halfOpenRequests, err := intEnv("CIRCUIT_BREAKER_HALF_OPEN_REQUESTS", 1)
+	if err != nil {
+		return CircuitBreakerConfig{}, err
+	}
+	// при HalfOpenRequests = 0 breaker навсегда остался бы в half-open, отклоняя все запросы
+	if failureThreshold < 1 || halfOpenRequests < 1 || coolDown <= 0 {
+		return CircuitBreakerConfig{}, errors.New("CIRCUIT_BREAKER_FAILURE_THRESHOLD, CIRCUIT_BREAKER_COOL_DOWN and CIRCUIT_BREAKER_HALF_OPEN_REQUESTS must be positive")
+	}
+
+	return CircuitBreakerConfig{
+		FailureThreshold: failureThreshold,
+		CoolDown:         coolDown,
+		HalfOpenRequests: halfOpenRequests,
+	}, nil
+}
+
+func initWebhook() (WebhookConfig, error) {
+	dispatchInterval, err := durationEnv("WEBHOOK_DISPATCH_INTERVAL", 5*time.Second)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	maxAttempts, err := intEnv("WEBHOOK_MAX_ATTEMPTS", 10)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	timeout, err := durationEnv("WEBHOOK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	batchSize, err := intEnv("WEBHOOK_BATCH_SIZE", 50)
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	if maxAttempts < 1 || batchSize < 1 {
+		return WebhookConfig{}, errors.New("WEBHOOK_MAX_ATTEMPTS and WEBHOOK_BATCH_SIZE must be positive")
+	}
+
+	return WebhookConfig{
+		DispatchInterval: dispatchInterval,
+		MaxAttempts:      maxAttempts,
+		Timeout:          timeout,
+		BatchSize:        batchSize,
+	}, nil
+}
+
+// initPII читает ключи шифрования. Без PHONE_ENCRYPTION_KEY_ID текущим считается последний ключ списка.
+func initPII() (PIIConfig, error) {
+	keys := make(map[string][]byte)
+	var lastID string
+	for _, entry := range strings.Split(os.Getenv("PHONE_ENCRYPTION_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return PIIConfig{}, errors.New("invalid PHONE_ENCRYPTION_KEYS: expected id:base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return PIIConfig{}, fmt.Errorf("invalid PHONE_ENCRYPTION_KEYS key %s: %w", id, err)
+		}
+		keys[id] = key
+		lastID = id
+	}
+	if len(keys) == 0 {
+		return PIIConfig{}, errors.New("PHONE_ENCRYPTION_KEYS is required but not set")
+	}
+
+	currentKeyID := os.Getenv("PHONE_ENCRYPTION_KEY_ID")
+	if currentKeyID == "" {
+		currentKeyID = lastID
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(os.Getenv("PHONE_INDEX_KEY"))
+	if err != nil {
+		return PIIConfig{}, fmt.Errorf("invalid PHONE_INDEX_KEY: %w", err)
+	}
+	if len(indexKey) == 0 {
+		return PIIConfig{}, errors.New("PHONE_INDEX_KEY is required but not set")
+	}
+
+	return PIIConfig{
+		EncryptionKeys: keys,
+		CurrentKeyID:   currentKeyID,
+		IndexKey:       indexKey,
+	}, nil
+}
+
+func initTracing() (TracingConfig, error) {
+	exporter := os.Getenv("TRACING_EXPORTER")
+	if exporter == "" {
+		exporter = "none"
+	}
+	switch exporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		if os.Getenv("TRACING_FILE") == "" {
+			return TracingConfig{}, errors.New("TRACING_FILE is required for file tracing exporter")
+		}
+	default:
+		return TracingConfig{}, fmt.Errorf("invalid TRACING_EXPORTER: %s", exporter)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "app_aggregator"
+	}
+
+	sampleRatio, err := floatEnv("TRACING_SAMPLE_RATIO", 1)
+	if err != nil {
+		return TracingConfig{}, err
+	}
+
+	return TracingConfig{
+		Exporter:    exporter,
+		FilePath:    os.Getenv("TRACING_FILE"),
+		ServiceName: serviceName,
+		SampleRatio: sampleRatio,
+	}, nil
+}
+
+func intEnv(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return number, nil
+}
+
+func floatEnv(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return number, nil
+}
+
+func boolEnv(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return flag, nil
+}
+
+func durationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return duration, nil
+}
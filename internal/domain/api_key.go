@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKey struct {
+	UUID             uuid.UUID  `json:"uuid"`
+	OrganizationUUID uuid.UUID  `json:"organization_uuid"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IssuedAPIKey содержит ключ в открытом виде, он возвращается только при выпуске
+type IssuedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+func APIKeyFromModel(model *models.APIKey) *APIKey {
+	if model == nil {
+		return nil
+	}
+
+	key := &APIKey{
+		Name:       model.Name,
+		Prefix:     model.Prefix,
+		CreatedAt:  model.CreatedAt,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+	}
+
+	if model.UUID != nil {
+		key.UUID = *model.UUID
+	}
+	if model.OrganizationUUID != nil {
+		key.OrganizationUUID = *model.OrganizationUUID
+	}
+
+	return key
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEntityLoanApplication = "loan_application"
+	AuditEntityOrganization    = "organization"
+	AuditEntitySettings        = "settings"
+)
+
+const (
+	AuditActionCreate     = "create"
+	AuditActionUpdate     = "update"
+	AuditActionDelete     = "delete"
+	AuditActionTransition = "transition"
+	AuditActionReroute    = "reroute"
+)
+
+type AuditEntry struct {
+	ID         uint            `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityUUID uuid.UUID       `json:"entity_uuid"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Diff       json.RawMessage `json:"diff,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	EntityType string
+	EntityUUID *uuid.UUID
+	Actor      string
+	Limit      int
+	Offset     int
+}
+
+func AuditEntryFromModel(model *models.AuditLog) *AuditEntry {
+	if model == nil {
+		return nil
+	}
+
+	entry := &AuditEntry{
+		ID:         model.ID,
+		EntityType: model.EntityType,
+		Actor:      model.Actor,
+		Action:     model.Action,
+		Before:     rawJSON(model.Before),
+		After:      rawJSON(model.After),
+		Diff:       rawJSON(model.Diff),
+		CreatedAt:  model.CreatedAt,
+	}
+
+	if model.EntityUUID != nil {
+		entry.EntityUUID = *model.EntityUUID
+	}
+
+	return entry
+}
+
+func rawJSON(value *string) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	return json.RawMessage(*value)
+}
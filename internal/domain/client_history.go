@@ -1,11 +1,44 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ClientLookupStatus string
+
+const (
+	ClientLookupFound    ClientLookupStatus = "found"
+	ClientLookupNotFound ClientLookupStatus = "not_found"
+	// ClientLookupUnknown - источник не ответил за отведенное время или вернул ошибку
+	ClientLookupUnknown ClientLookupStatus = "unknown"
+)
+
 type ClientHistory struct {
-	ClientID         string
-	OrganizationName string
-	HasActiveLoan    bool
-	ActiveLoanNumber string
-	LastPdn          string
-	HasLoans         bool
-	ClientFullName   string
+	Status           ClientLookupStatus `json:"status"`
+	Source           string             `json:"source"`
+	ClientID         string             `json:"client_id"`
+	OrganizationUUID uuid.UUID          `json:"organization_uuid"`
+	OrganizationName string             `json:"organization_name,omitempty"`
+	HasActiveLoan    bool               `json:"has_active_loan"`
+	ActiveLoanNumber string             `json:"active_loan_number,omitempty"`
+	LastPdn          string             `json:"last_pdn,omitempty"`
+	HasLoans         bool               `json:"has_loans"`
+	ClientFullName   string             `json:"client_full_name,omitempty"`
+}
+
+// ClientSourceHealth - состояние circuit breaker legacy источника
+type ClientSourceHealth struct {
+	Name                string     `json:"name"`
+	OrganizationUUID    uuid.UUID  `json:"organization_uuid"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	TotalSuccesses      int        `json:"total_successes"`
+	TotalFailures       int        `json:"total_failures"`
+	Rejected            int        `json:"rejected"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
 }
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DedupScope - среди каких заявок ищутся дубли
+type DedupScope string
+
+const (
+	DedupScopeIncomingOrganization DedupScope = "incoming_organization"
+	DedupScopeGlobal               DedupScope = "global"
+)
+
+// DedupAction - что делать с найденным дублем
+type DedupAction string
+
+const (
+	DedupActionReject DedupAction = "reject"
+	DedupActionMerge  DedupAction = "merge"
+	DedupActionLink   DedupAction = "link"
+)
+
+const (
+	DefaultDedupWindowHours = 24
+	MaxDedupWindowHours     = 24 * 365
+)
+
+type DedupPolicy struct {
+	OrganizationUUID uuid.UUID   `json:"organization_uuid"`
+	WindowHours      int         `json:"window_hours"`
+	Scope            DedupScope  `json:"scope"`
+	Action           DedupAction `json:"action"`
+	Default          bool        `json:"default"`
+	UpdatedAt        time.Time   `json:"updated_at,omitempty"`
+}
+
+// DefaultDedupPolicy действует для организаций без своей политики:
+// заявка с тем же телефоном за последние сутки от любой организации отклоняется
+func DefaultDedupPolicy(organizationUUID uuid.UUID) *DedupPolicy {
+	return &DedupPolicy{
+		OrganizationUUID: organizationUUID,
+		WindowHours:      DefaultDedupWindowHours,
+		Scope:            DedupScopeGlobal,
+		Action:           DedupActionReject,
+		Default:          true,
+	}
+}
+
+func (p *DedupPolicy) Valid() bool {
+	if p.WindowHours < 1 || p.WindowHours > MaxDedupWindowHours {
+		return false
+	}
+	switch p.Scope {
+	case DedupScopeIncomingOrganization, DedupScopeGlobal:
+	default:
+		return false
+	}
+	switch p.Action {
+	case DedupActionReject, DedupActionMerge, DedupActionLink:
+		return true
+	}
+	return false
+}
+
+func (p *DedupPolicy) Window() time.Duration {
+	return time.Duration(p.WindowHours) * time.Hour
+}
+
+func DedupPolicyFromModel(model *models.DedupPolicy) *DedupPolicy {
+	if model == nil {
+		return nil
+	}
+
+	policy := &DedupPolicy{
+		WindowHours: model.WindowHours,
+		Scope:       DedupScope(model.Scope),
+		Action:      DedupAction(model.Action),
+		UpdatedAt:   model.UpdatedAt,
+	}
+
+	if model.OrganizationUUID != nil {
+		policy.OrganizationUUID = *model.OrganizationUUID
+	}
+
+	return policy
+}
+
+type LoanApplicationDuplicate struct {
+	ID                       uint        `json:"id"`
+	OriginalUUID             uuid.UUID   `json:"original_uuid"`
+	DuplicateUUID            *uuid.UUID  `json:"duplicate_uuid,omitempty"`
+	IncomingOrganizationName string      `json:"incoming_organization_name"`
+	Action                   DedupAction `json:"action"`
+	CreatedAt                time.Time   `json:"created_at"`
+}
+
+// DuplicateChain - исходная заявка и все попытки подать ее повторно
+type DuplicateChain struct {
+	OriginalUUID uuid.UUID                   `json:"original_uuid"`
+	Duplicates   []*LoanApplicationDuplicate `json:"duplicates"`
+}
+
+func LoanApplicationDuplicateFromModel(model *models.LoanApplicationDuplicate) *LoanApplicationDuplicate {
+	if model == nil {
+		return nil
+	}
+
+	duplicate := &LoanApplicationDuplicate{
+		ID:                       model.ID,
+		DuplicateUUID:            model.DuplicateUUID,
+		IncomingOrganizationName: model.IncomingOrganization.Name,
+		Action:                   DedupAction(model.Action),
+		CreatedAt:                model.CreatedAt,
+	}
+
+	if model.OriginalUUID != nil {
+		duplicate.OriginalUUID = *model.OriginalUUID
+	}
+
+	return duplicate
+}
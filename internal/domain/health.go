@@ -0,0 +1,32 @@
+package domain
+
+const (
+	DependencyUp   = "up"
+	DependencyDown = "down"
+)
+
+const (
+	ReadinessOK          = "ok"
+	ReadinessDegraded    = "degraded"
+	ReadinessUnavailable = "unavailable"
+)
+
+type DependencyHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Required  bool   `json:"required"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ReadinessReport - результат проверки зависимостей.
+// Status unavailable, если недоступна хотя бы одна обязательная зависимость,
+// degraded - если недоступны только необязательные.
+type ReadinessReport struct {
+	Status       string              `json:"status"`
+	Dependencies []*DependencyHealth `json:"dependencies"`
+}
+
+func (r *ReadinessReport) Ready() bool {
+	return r.Status != ReadinessUnavailable
+}
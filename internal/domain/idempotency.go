@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IdempotencyRecord struct {
+	OrganizationUUID uuid.UUID
+	Key              string
+	RequestHash      string
+	StatusCode       int
+	ResponseBody     []byte
+	CreatedAt        time.Time
+	ExpiresAt        time.Time
+}
+
+// Completed сообщает, что ответ на запрос уже сохранен
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+func IdempotencyRecordFromModel(model *models.IdempotencyKey) *IdempotencyRecord {
+	if model == nil {
+		return nil
+	}
+
+	record := &IdempotencyRecord{
+		Key:          model.Key,
+		RequestHash:  model.RequestHash,
+		StatusCode:   model.StatusCode,
+		ResponseBody: model.ResponseBody,
+		CreatedAt:    model.CreatedAt,
+		ExpiresAt:    model.ExpiresAt,
+	}
+
+	if model.OrganizationUUID != nil {
+		record.OrganizationUUID = *model.OrganizationUUID
+	}
+
+	return record
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusCompleted ImportJobStatus = "completed"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated   ImportRowStatus = "created"
+	ImportRowDuplicate ImportRowStatus = "duplicate"
+	ImportRowInvalid   ImportRowStatus = "invalid"
+	ImportRowFailed    ImportRowStatus = "failed"
+)
+
+// Причины в построчном отчете импорта - стабильные коды, текст ошибок в отчет не попадает
+const (
+	ImportReasonInvalidPhone           = "invalid_phone"
+	ImportReasonPhoneCountryNotAllowed = "phone_country_not_allowed"
+	ImportReasonInvalidValue           = "invalid_value"
+	ImportReasonValueTooLow            = "value_too_low"
+	ImportReasonDuplicate              = "duplicate"
+	ImportReasonMerged                 = "merged"
+	ImportReasonNoIssuingOrganization  = "no_issuing_organization"
+	ImportReasonInternalError          = "internal_error"
+)
+
+// ImportColumnMapping - заголовки колонок файла, из которых берутся поля заявки
+type ImportColumnMapping struct {
+	Phone   string `json:"phone"`
+	Value   string `json:"value"`
+	Comment string `json:"comment"`
+}
+
+// DefaultImportColumnMapping - колонки phone, value и comment
+func DefaultImportColumnMapping() ImportColumnMapping {
+	return ImportColumnMapping{
+		Phone:   "phone",
+		Value:   "value",
+		Comment: "comment",
+	}
+}
+
+// ImportRow - строка файла как есть, Line - ее номер в файле, заголовок - первая строка
+type ImportRow struct {
+	Line    int
+	Phone   string
+	Value   string
+	Comment string
+}
+
+// ImportRowResult - итог обработки одной строки файла
+type ImportRowResult struct {
+	Line                int             `json:"line"`
+	Status              ImportRowStatus `json:"status"`
+	Reason              string          `json:"reason,omitempty"`
+	LoanApplicationUUID *uuid.UUID      `json:"loan_application_uuid,omitempty"`
+}
+
+type ImportJob struct {
+	UUID             uuid.UUID       `json:"uuid"`
+	OrganizationUUID uuid.UUID       `json:"organization_uuid"`
+	FileName         string          `json:"file_name"`
+	Status           ImportJobStatus `json:"status"`
+	TotalRows        int             `json:"total_rows"`
+	ProcessedRows    int             `json:"processed_rows"`
+	Created          int             `json:"created"`
+	Duplicates       int             `json:"duplicates"`
+	Invalid          int             `json:"invalid"`
+	Failed           int             `json:"failed"`
+	Error            string          `json:"error,omitempty"`
+	Actor            string          `json:"actor"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	FinishedAt       *time.Time      `json:"finished_at,omitempty"`
+}
+
+// Count учитывает результат строки в счетчиках задачи
+func (j *ImportJob) Count(result *ImportRowResult) {
+	j.ProcessedRows++
+	switch result.Status {
+	case ImportRowCreated:
+		j.Created++
+	case ImportRowDuplicate:
+		j.Duplicates++
+	case ImportRowInvalid:
+		j.Invalid++
+	default:
+		j.Failed++
+	}
+}
+
+type ImportRowFilter struct {
+	Status ImportRowStatus
+	Limit  int
+	Offset int
+}
+
+func ImportJobFromModel(model *models.ImportJob) *ImportJob {
+	if model == nil {
+		return nil
+	}
+
+	job := &ImportJob{
+		FileName:      model.FileName,
+		Status:        ImportJobStatus(model.Status),
+		TotalRows:     model.TotalRows,
+		ProcessedRows: model.ProcessedRows,
+		Created:       model.Created,
+		Duplicates:    model.Duplicates,
+		Invalid:       model.Invalid,
+		Failed:        model.Failed,
+		Error:         model.Error,
+		Actor:         model.Actor,
+		CreatedAt:     model.CreatedAt,
+		UpdatedAt:     model.UpdatedAt,
+		FinishedAt:    model.FinishedAt,
+	}
+
+	if model.UUID != nil {
+		job.UUID = *model.UUID
+	}
+	if model.OrganizationUUID != nil {
+		job.OrganizationUUID = *model.OrganizationUUID
+	}
+
+	return job
+}
+
+func ImportRowResultFromModel(model *models.ImportJobRow) *ImportRowResult {
+	if model == nil {
+		return nil
+	}
+
+	return &ImportRowResult{
+		Line:                model.Line,
+		Status:              ImportRowStatus(model.Status),
+		Reason:              model.Reason,
+		LoanApplicationUUID: model.LoanApplicationUUID,
+	}
+}
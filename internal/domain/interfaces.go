@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,11 +18,36 @@ type OrganizationRepository interface {
 }
 
 type LoanApplicationRepository interface {
-	GetAll(ctx context.Context) ([]*LoanApplication, error)
+	GetAll(ctx context.Context, filter *LoanApplicationFilter) (*LoanApplicationPage, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*LoanApplication, error)
-	Create(ctx context.Context, app *LoanApplication) (*LoanApplication, error)
+	Deduplicate(ctx context.Context, app *LoanApplication) (*LoanApplication, error)
+	Create(ctx context.Context, app *LoanApplication, decision *RoutingDecision) (*LoanApplication, error)
 	Update(ctx context.Context, app *LoanApplication) (*LoanApplication, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to LoanApplicationStatus) (*LoanApplication, error)
+	UpdateRouting(ctx context.Context, id uuid.UUID, from LoanApplicationStatus, decision *RoutingDecision, unknownSources []string) (*LoanApplication, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	EncryptPhones(ctx context.Context, batchSize int) (int, error)
+}
+
+// ClientSource - legacy база кредитора, в которой ищутся клиенты по телефону
+type ClientSource interface {
+	Name() string
+	OrganizationUUID() uuid.UUID
+	Timeout() time.Duration
+	FindClient(ctx context.Context, phone string) (string, error)
+	LoanHistory(ctx context.Context, clientID string) (*ClientHistory, error)
+}
+
+type ClientRepository interface {
+	FindClientHistory(ctx context.Context, phone string) ([]*ClientHistory, error)
+	SourcesHealth(ctx context.Context) ([]*ClientSourceHealth, error)
+}
+
+type SettingsRepository interface {
+	GetAll(ctx context.Context) ([]*Settings, error)
+	GetByOrganization(ctx context.Context, organizationID uuid.UUID) (*Settings, error)
+	Save(ctx context.Context, settings *Settings) (*Settings, error)
+	Versions(ctx context.Context, organizationID uuid.UUID) ([]*SettingsVersion, error)
 }
 
 type OrganizationService interface {
@@ -32,9 +59,125 @@ type OrganizationService interface {
 }
 
 type LoanApplicationService interface {
-	GetAll(ctx context.Context) ([]*LoanApplication, error)
+	GetAll(ctx context.Context, filter *LoanApplicationFilter) (*LoanApplicationPage, error)
+	Export(ctx context.Context, filter *LoanApplicationFilter, fn func(*LoanApplication) error) error
 	GetByID(ctx context.Context, id uuid.UUID) (*LoanApplication, error)
 	Create(ctx context.Context, app *LoanApplication) (*LoanApplication, error)
 	Update(ctx context.Context, id uuid.UUID, app *LoanApplication) (*LoanApplication, error)
+	Transition(ctx context.Context, id uuid.UUID, status LoanApplicationStatus) (*LoanApplication, error)
+	Reroute(ctx context.Context, id uuid.UUID) (*LoanApplication, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+type SettingsService interface {
+	GetAll(ctx context.Context) ([]*Settings, error)
+	GetByOrganization(ctx context.Context, organizationID uuid.UUID) (*Settings, error)
+	Save(ctx context.Context, settings *Settings) (*Settings, error)
+	Versions(ctx context.Context, organizationID uuid.UUID) ([]*SettingsVersion, error)
+}
+
+type ClientService interface {
+	GetHistory(ctx context.Context, phone string) ([]*ClientHistory, error)
+	GetSourcesHealth(ctx context.Context) ([]*ClientSourceHealth, error)
+}
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, organizationID uuid.UUID, key, requestHash string, expiresAt time.Time) (*IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, organizationID uuid.UUID, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, organizationID uuid.UUID, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, organizationID uuid.UUID, key, requestHash string) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, organizationID uuid.UUID, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, organizationID uuid.UUID, key string) error
+}
+
+type DedupRepository interface {
+	GetPolicy(ctx context.Context, organizationID uuid.UUID) (*DedupPolicy, error)
+	SavePolicy(ctx context.Context, policy *DedupPolicy) (*DedupPolicy, error)
+	DuplicateChain(ctx context.Context, loanApplicationID uuid.UUID) (*DuplicateChain, error)
+}
+
+type DedupService interface {
+	GetPolicy(ctx context.Context, organizationID uuid.UUID) (*DedupPolicy, error)
+	SavePolicy(ctx context.Context, policy *DedupPolicy) (*DedupPolicy, error)
+	DuplicateChain(ctx context.Context, loanApplicationID uuid.UUID) (*DuplicateChain, error)
+}
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, organizationID uuid.UUID, url, secret string, eventTypes []string) (*WebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, organizationID uuid.UUID) ([]*WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, organizationID, id uuid.UUID) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) error
+	MarkFailed(ctx context.Context, id uuid.UUID, statusCode *int, lastError string, nextAttemptAt *time.Time) error
+	ListDeliveries(ctx context.Context, filter *WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id uuid.UUID) (*WebhookDelivery, error)
+}
+
+type WebhookService interface {
+	CreateEndpoint(ctx context.Context, organizationID uuid.UUID, url string, eventTypes []string) (*IssuedWebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, organizationID uuid.UUID) ([]*WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, organizationID, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, filter *WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id uuid.UUID) (*WebhookDelivery, error)
+}
+
+type ImportRepository interface {
+	CreateJob(ctx context.Context, job *ImportJob) (*ImportJob, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*ImportJob, error)
+	SaveProgress(ctx context.Context, job *ImportJob, results []*ImportRowResult) error
+	ListRows(ctx context.Context, jobID uuid.UUID, filter *ImportRowFilter) ([]*ImportRowResult, error)
+	FailStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ImportService interface {
+	Start(ctx context.Context, organizationID uuid.UUID, fileName string, file io.Reader, mapping ImportColumnMapping) (*ImportJob, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*ImportJob, error)
+	ListRows(ctx context.Context, jobID uuid.UUID, filter *ImportRowFilter) ([]*ImportRowResult, error)
+}
+
+type HealthService interface {
+	Readiness(ctx context.Context) *ReadinessReport
+}
+
+type AuditRepository interface {
+	List(ctx context.Context, filter *AuditFilter) ([]*AuditEntry, error)
+}
+
+type AuditService interface {
+	List(ctx context.Context, filter *AuditFilter) ([]*AuditEntry, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, organizationID uuid.UUID, name, prefix, keyHash string) (*APIKey, error)
+	GetByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*APIKey, error)
+	Revoke(ctx context.Context, organizationID, id uuid.UUID) error
+	FindOrganizationByHash(ctx context.Context, keyHash string) (*Organization, error)
+}
+
+type APIKeyService interface {
+	Issue(ctx context.Context, organizationID uuid.UUID, name string) (*IssuedAPIKey, error)
+	GetByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*APIKey, error)
+	Revoke(ctx context.Context, organizationID, id uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*Organization, error)
+}
+
+type OperatorRepository interface {
+	GetAll(ctx context.Context) ([]*Operator, error)
+	Create(ctx context.Context, login, passwordHash string, role OperatorRole) (*Operator, error)
+	FindCredentials(ctx context.Context, login string) (*Operator, string, error)
+	CreateToken(ctx context.Context, operatorID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*Operator, error)
+	RevokeToken(ctx context.Context, tokenHash string) error
+}
+
+type OperatorService interface {
+	GetAll(ctx context.Context) ([]*Operator, error)
+	Create(ctx context.Context, login, password string, role OperatorRole) (*Operator, error)
+	Login(ctx context.Context, login, password string) (*OperatorToken, error)
+	Logout(ctx context.Context, token string) error
+	Authenticate(ctx context.Context, token string) (*Operator, error)
+}
@@ -2,20 +2,30 @@ package domain
 
 import (
 	"app_aggregator/internal/models"
+	"app_aggregator/pkg/validators"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type LoanApplication struct {
-	UUID                     uuid.UUID `json:"uuid"`
-	IncomingOrganizationName string    `json:"incoming_organization_name" validate:"required"`
-	IssueOrganizationName    string    `json:"issue_organization_name" validate:"required"`
-	Value                    int64     `json:"value" validate:"required"`
-	Phone                    string    `json:"phone" validate:"required"`
-	Comment                  string    `json:"comment"`
-	CreatedAt                time.Time `json:"created_at"`
-	UpdatedAt                time.Time `json:"updated_at"`
+	UUID                     uuid.UUID             `json:"uuid"`
+	IncomingOrganizationName string                `json:"incoming_organization_name" validate:"required"`
+	IssueOrganizationName    string                `json:"issue_organization_name" validate:"required"`
+	Value                    int64                 `json:"value" validate:"required"`
+	Phone                    string                `json:"phone" validate:"required"`
+	PhoneCountry             string                `json:"phone_country,omitempty"`
+	PhoneType                string                `json:"phone_type,omitempty"`
+	Comment                  string                `json:"comment"`
+	Status                   LoanApplicationStatus `json:"status"`
+	RoutingReason            string                `json:"routing_reason,omitempty"`
+	UnknownSources           []string              `json:"unknown_sources,omitempty"`
+	DuplicateOfUUID          *uuid.UUID            `json:"duplicate_of_uuid,omitempty"`
+	SettingsVersion          *int                  `json:"settings_version,omitempty"`
+	Merged                   bool                  `json:"merged,omitempty"`
+	CreatedAt                time.Time             `json:"created_at"`
+	UpdatedAt                time.Time             `json:"updated_at"`
 }
 
 func NewLoanApplication(incomingOrgName, issueOrgName, phone string, value int64, comment string) *LoanApplication {
@@ -26,6 +36,7 @@ func NewLoanApplication(incomingOrgName, issueOrgName, phone string, value int64
 		Value:                    value,
 		Phone:                    phone,
 		Comment:                  comment,
+		Status:                   LoanApplicationStatusNew,
 		CreatedAt:                time.Now(),
 		UpdatedAt:                time.Now(),
 	}
@@ -41,7 +52,14 @@ func LoanApplicationFromModel(model *models.LoanApplication) *LoanApplication {
 		IssueOrganizationName:    model.IssueOrganization.Name,
 		Value:                    model.Value,
 		Phone:                    model.Phone,
+		PhoneCountry:             model.PhoneCountry,
+		PhoneType:                model.PhoneType,
 		Comment:                  model.Comment,
+		Status:                   LoanApplicationStatus(model.Status),
+		RoutingReason:            model.RoutingReason,
+		UnknownSources:           splitSources(model.UnknownSources),
+		DuplicateOfUUID:          model.DuplicateOfUUID,
+		SettingsVersion:          model.SettingsVersion,
 		CreatedAt:                model.CreatedAt,
 		UpdatedAt:                model.UpdatedAt,
 	}
@@ -55,9 +73,15 @@ func LoanApplicationFromModel(model *models.LoanApplication) *LoanApplication {
 
 func (la *LoanApplication) ToModel() *models.LoanApplication {
 	model := &models.LoanApplication{
-		Value:   la.Value,
-		Phone:   la.Phone,
-		Comment: la.Comment,
+		Value:           la.Value,
+		Phone:           la.Phone,
+		PhoneCountry:    la.PhoneCountry,
+		PhoneType:       la.PhoneType,
+		Comment:         la.Comment,
+		Status:          string(la.Status),
+		RoutingReason:   la.RoutingReason,
+		UnknownSources:  strings.Join(la.UnknownSources, ","),
+		SettingsVersion: la.SettingsVersion,
 	}
 
 	if la.UUID != uuid.Nil {
@@ -76,6 +100,27 @@ func (la *LoanApplication) UpdateFromModel(model *models.LoanApplication) {
 	la.IssueOrganizationName = model.IssueOrganization.Name
 	la.Value = model.Value
 	la.Phone = model.Phone
+	la.PhoneCountry = model.PhoneCountry
+	la.PhoneType = model.PhoneType
 	la.Comment = model.Comment
+	la.Status = LoanApplicationStatus(model.Status)
+	la.RoutingReason = model.RoutingReason
+	la.UnknownSources = splitSources(model.UnknownSources)
+	la.DuplicateOfUUID = model.DuplicateOfUUID
+	la.SettingsVersion = model.SettingsVersion
 	la.UpdatedAt = model.UpdatedAt
 }
+
+// Masked возвращает копию заявки со скрытым телефоном: 7912***4567
+func (la *LoanApplication) Masked() *LoanApplication {
+	masked := *la
+	masked.Phone = validators.MaskPhone(la.Phone)
+	return &masked
+}
+
+func splitSources(sources string) []string {
+	if sources == "" {
+		return nil
+	}
+	return strings.Split(sources, ",")
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LoanApplicationSortCreatedAt = "created_at"
+	LoanApplicationSortValue     = "value"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+type LoanApplicationFilter struct {
+	IncomingOrganizationUUID *uuid.UUID
+	IssueOrganizationUUID    *uuid.UUID
+	Phone                    string
+	MinValue                 *int64
+	MaxValue                 *int64
+	CreatedFrom              *time.Time
+	CreatedTo                *time.Time
+	Status                   LoanApplicationStatus
+	Sort                     string
+	Order                    string
+	Cursor                   string
+	Limit                    int
+}
+
+type LoanApplicationPage struct {
+	Items      []*LoanApplication `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
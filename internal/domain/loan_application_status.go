@@ -0,0 +1,27 @@
+package domain
+
+type LoanApplicationStatus string
+
+const (
+	LoanApplicationStatusNew       LoanApplicationStatus = "new"
+	LoanApplicationStatusRouted    LoanApplicationStatus = "routed"
+	LoanApplicationStatusInReview  LoanApplicationStatus = "in_review"
+	LoanApplicationStatusApproved  LoanApplicationStatus = "approved"
+	LoanApplicationStatusRejected  LoanApplicationStatus = "rejected"
+	LoanApplicationStatusIssued    LoanApplicationStatus = "issued"
+	LoanApplicationStatusCancelled LoanApplicationStatus = "cancelled"
+)
+
+func (s LoanApplicationStatus) Valid() bool {
+	switch s {
+	case LoanApplicationStatusNew,
+		LoanApplicationStatusRouted,
+		LoanApplicationStatusInReview,
+		LoanApplicationStatusApproved,
+		LoanApplicationStatusRejected,
+		LoanApplicationStatusIssued,
+		LoanApplicationStatusCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OperatorRole string
+
+const (
+	OperatorRoleViewer   OperatorRole = "viewer"
+	OperatorRoleOperator OperatorRole = "operator"
+	OperatorRoleAdmin    OperatorRole = "admin"
+)
+
+func (r OperatorRole) Valid() bool {
+	switch r {
+	case OperatorRoleViewer, OperatorRoleOperator, OperatorRoleAdmin:
+		return true
+	}
+	return false
+}
+
+type Operator struct {
+	UUID      uuid.UUID    `json:"uuid"`
+	Login     string       `json:"login"`
+	Role      OperatorRole `json:"role"`
+	Disabled  bool         `json:"disabled"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type OperatorToken struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	Role      OperatorRole `json:"role"`
+}
+
+func OperatorFromModel(model *models.Operator) *Operator {
+	if model == nil {
+		return nil
+	}
+
+	operator := &Operator{
+		Login:     model.Login,
+		Role:      OperatorRole(model.Role),
+		Disabled:  model.Disabled,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if model.UUID != nil {
+		operator.UUID = *model.UUID
+	}
+
+	return operator
+}
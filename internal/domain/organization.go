@@ -2,6 +2,7 @@ package domain
 
 import (
 	"app_aggregator/internal/models"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,8 @@ type Organization struct {
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" db:"deleted_at"`
 	UUID      *uuid.UUID     `json:"uuid" db:"uuid"`
 	Name      string         `json:"name" db:"name" validate:"required"`
+	// AllowedPhoneCountries - страны телефонов в заявках организации, пусто - любые поддерживаемые
+	AllowedPhoneCountries []string `json:"allowed_phone_countries"`
 }
 
 func FromModel(model *models.Organization) *Organization {
@@ -22,17 +25,19 @@ func FromModel(model *models.Organization) *Organization {
 	}
 
 	return &Organization{
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
-		DeletedAt: model.DeletedAt,
-		UUID:      model.UUID,
-		Name:      model.Name,
+		CreatedAt:             model.CreatedAt,
+		UpdatedAt:             model.UpdatedAt,
+		DeletedAt:             model.DeletedAt,
+		UUID:                  model.UUID,
+		Name:                  model.Name,
+		AllowedPhoneCountries: splitPhoneCountries(model.AllowedPhoneCountries),
 	}
 }
 
 func (o *Organization) ToModel() *models.Organization {
 	model := &models.Organization{
-		Name: o.Name,
+		Name:                  o.Name,
+		AllowedPhoneCountries: strings.Join(o.AllowedPhoneCountries, ","),
 	}
 
 	if o.UUID != nil {
@@ -41,3 +46,11 @@ func (o *Organization) ToModel() *models.Organization {
 
 	return model
 }
+
+// splitPhoneCountries всегда возвращает не nil, чтобы в JSON было [] вместо null
+func splitPhoneCountries(countries string) []string {
+	if countries == "" {
+		return []string{}
+	}
+	return strings.Split(countries, ",")
+}
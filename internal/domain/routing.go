@@ -0,0 +1,80 @@
+package domain
+
+import "strconv"
+
+// ClientProfile собирает историю клиента по всем legacy базам
+type ClientProfile struct {
+	Phone     string
+	Histories []*ClientHistory
+}
+
+// RoutingDecision - выбранная организация и версия ее настроек, по которой принято решение
+type RoutingDecision struct {
+	Organization    *Organization
+	Reason          string
+	SettingsVersion int
+}
+
+func NewClientProfile(phone string, histories []*ClientHistory) *ClientProfile {
+	return &ClientProfile{
+		Phone:     phone,
+		Histories: histories,
+	}
+}
+
+// IsNewClient - у клиента нет займов ни в одной базе. Если часть баз не ответила,
+// займы могут быть в них, поэтому клиент новым не считается.
+func (p *ClientProfile) IsNewClient() bool {
+	for _, history := range p.Histories {
+		if history.HasLoans || history.Status == ClientLookupUnknown {
+			return false
+		}
+	}
+	return true
+}
+
+// HasActiveLoan - у клиента есть действующий займ. Если часть баз не ответила,
+// действующий займ в них не исключен, поэтому возвращается true.
+func (p *ClientProfile) HasActiveLoan() bool {
+	for _, history := range p.Histories {
+		if history.HasActiveLoan || history.Status == ClientLookupUnknown {
+			return true
+		}
+	}
+	return false
+}
+
+// Incomplete - история собрана не по всем базам, см. UnknownSources
+func (p *ClientProfile) Incomplete() bool {
+	return len(p.UnknownSources()) > 0
+}
+
+// UnknownSources возвращает источники, по которым не удалось получить историю
+func (p *ClientProfile) UnknownSources() []string {
+	var sources []string
+	for _, history := range p.Histories {
+		if history.Status == ClientLookupUnknown {
+			sources = append(sources, history.Source)
+		}
+	}
+	return sources
+}
+
+// Pdn возвращает максимальный последний ПДН клиента среди всех баз без округления:
+// 45.9 не должен проходить лимит 45
+func (p *ClientProfile) Pdn() float64 {
+	var pdn float64
+	for _, history := range p.Histories {
+		if history.LastPdn == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(history.LastPdn, 64)
+		if err != nil {
+			continue
+		}
+		if value > pdn {
+			pdn = value
+		}
+	}
+	return pdn
+}
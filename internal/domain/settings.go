@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Settings struct {
+	UUID             uuid.UUID `json:"uuid"`
+	OrganizationUUID uuid.UUID `json:"organization_uuid"`
+	OrganizationName string    `json:"organization_name"`
+	NewClient        bool      `json:"new_client"`
+	PDN              int64     `json:"pdn"`
+	HasDebt          bool      `json:"has_debt"`
+	Version          int       `json:"version"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// SettingsVersion - настройки организации в одной из прошлых или текущей версии
+type SettingsVersion struct {
+	OrganizationUUID uuid.UUID `json:"organization_uuid"`
+	Version          int       `json:"version"`
+	NewClient        bool      `json:"new_client"`
+	PDN              int64     `json:"pdn"`
+	HasDebt          bool      `json:"has_debt"`
+	Actor            string    `json:"actor"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func SettingsFromModel(model *models.Settings) *Settings {
+	if model == nil {
+		return nil
+	}
+
+	settings := &Settings{
+		OrganizationName: model.Organization.Name,
+		NewClient:        model.NewClient,
+		PDN:              model.PDN,
+		HasDebt:          model.HasDebt,
+		Version:          model.Version,
+		CreatedAt:        model.CreatedAt,
+		UpdatedAt:        model.UpdatedAt,
+	}
+
+	if model.UUID != nil {
+		settings.UUID = *model.UUID
+	}
+	if model.OrganisationUUID != nil {
+		settings.OrganizationUUID = *model.OrganisationUUID
+	}
+
+	return settings
+}
+
+func SettingsVersionFromModel(model *models.SettingsVersion) *SettingsVersion {
+	if model == nil {
+		return nil
+	}
+
+	version := &SettingsVersion{
+		Version:   model.Version,
+		NewClient: model.NewClient,
+		PDN:       model.PDN,
+		HasDebt:   model.HasDebt,
+		Actor:     model.Actor,
+		CreatedAt: model.CreatedAt,
+	}
+
+	if model.OrganisationUUID != nil {
+		version.OrganizationUUID = *model.OrganisationUUID
+	}
+
+	return version
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"app_aggregator/internal/models"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventLoanApplicationCreated       = "loan_application.created"
+	EventLoanApplicationUpdated       = "loan_application.updated"
+	EventLoanApplicationRouted        = "loan_application.routed"
+	EventLoanApplicationStatusChanged = "loan_application.status_changed"
+)
+
+// ValidEventType сообщает, можно ли подписаться на событие
+func ValidEventType(eventType string) bool {
+	switch eventType {
+	case EventLoanApplicationCreated, EventLoanApplicationUpdated,
+		EventLoanApplicationRouted, EventLoanApplicationStatusChanged:
+		return true
+	}
+	return false
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookEvent - тело запроса, отправляемого на webhook
+type WebhookEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookEndpoint - адрес организации для уведомлений. Пустой EventTypes - подписка на все события.
+type WebhookEndpoint struct {
+	UUID             uuid.UUID `json:"uuid"`
+	OrganizationUUID uuid.UUID `json:"organization_uuid"`
+	URL              string    `json:"url"`
+	EventTypes       []string  `json:"event_types"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Subscribed сообщает, нужно ли отправлять событие eventType на этот адрес
+func (e *WebhookEndpoint) Subscribed(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, subscribed := range e.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuedWebhookEndpoint - созданный адрес вместе с секретом подписи, который показывается один раз
+type IssuedWebhookEndpoint struct {
+	*WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+func WebhookEndpointFromModel(model *models.WebhookEndpoint) *WebhookEndpoint {
+	if model == nil {
+		return nil
+	}
+
+	endpoint := &WebhookEndpoint{
+		URL:        model.URL,
+		EventTypes: splitSources(model.EventTypes),
+		CreatedAt:  model.CreatedAt,
+	}
+
+	if model.UUID != nil {
+		endpoint.UUID = *model.UUID
+	}
+	if model.OrganizationUUID != nil {
+		endpoint.OrganizationUUID = *model.OrganizationUUID
+	}
+
+	return endpoint
+}
+
+// JoinEventTypes - формат хранения списка событий в webhook_endpoints
+func JoinEventTypes(eventTypes []string) string {
+	return strings.Join(eventTypes, ",")
+}
+
+type WebhookDelivery struct {
+	UUID           uuid.UUID       `json:"uuid"`
+	EventUUID      uuid.UUID       `json:"event_uuid"`
+	EventType      string          `json:"event_type"`
+	EndpointUUID   uuid.UUID       `json:"endpoint_uuid"`
+	URL            string          `json:"url"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	Secret         string          `json:"-"`
+}
+
+type WebhookDeliveryFilter struct {
+	Status           string
+	OrganizationUUID *uuid.UUID
+	Limit            int
+	Offset           int
+}
+
+func WebhookDeliveryFromModel(model *models.WebhookDelivery) *WebhookDelivery {
+	if model == nil {
+		return nil
+	}
+
+	delivery := &WebhookDelivery{
+		EventType:      model.Event.EventType,
+		URL:            model.Endpoint.URL,
+		Status:         model.Status,
+		Attempts:       model.Attempts,
+		NextAttemptAt:  model.NextAttemptAt,
+		LastStatusCode: model.LastStatusCode,
+		LastError:      model.LastError,
+		DeliveredAt:    model.DeliveredAt,
+		CreatedAt:      model.CreatedAt,
+		Payload:        json.RawMessage(model.Event.Payload),
+		Secret:         model.Endpoint.Secret,
+	}
+
+	if model.UUID != nil {
+		delivery.UUID = *model.UUID
+	}
+	if model.Event.UUID != nil {
+		delivery.EventUUID = *model.Event.UUID
+	}
+	if model.Endpoint.UUID != nil {
+		delivery.EndpointUUID = *model.Endpoint.UUID
+	}
+
+	return delivery
+}
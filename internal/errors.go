@@ -1,13 +1,61 @@
 package internal
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
 
 var (
-	ErrRecordNoFound           = errors.New("no record found")
-	ErrPhoneFormat             = errors.New("invalid phone format")
-	ErrPhoneNumberExistToday   = errors.New("phone number already exists today")
-	ErrInvalidPhoneNumber      = errors.New("invalid phone number")
-	ErrEmptyPhoneNumber        = errors.New("empty phone number")
-	ErrInvalidOrganizationName = errors.New("invalid organization name")
-	ErrInvalidLoanApplication  = errors.New("invalid loan application")
+	ErrRecordNoFound            = errors.New("no record found")
+	ErrPhoneFormat              = errors.New("invalid phone format")
+	ErrDuplicateLoanApplication = errors.New("loan application with this phone already exists within the dedup window")
+	ErrInvalidPhoneNumber       = errors.New("invalid phone number")
+	ErrEmptyPhoneNumber         = errors.New("empty phone number")
+	ErrPhoneCountryNotAllowed   = errors.New("phone number country is not allowed for the organization")
+	ErrInvalidOrganizationName  = errors.New("invalid organization name")
+	ErrInvalidPhoneCountries    = errors.New("unsupported phone country in allowed_phone_countries")
+	ErrInvalidLoanApplication   = errors.New("invalid loan application")
+	ErrClientSourceExists       = errors.New("client source already registered")
+	ErrInvalidStatus            = errors.New("invalid loan application status")
+	ErrIllegalTransition        = errors.New("illegal loan application status transition")
+	ErrInvalidAuditFilter       = errors.New("invalid audit filter")
+	ErrInvalidAPIKey            = errors.New("invalid api key")
+	ErrInvalidAPIKeyName        = errors.New("invalid api key name")
+	ErrInvalidOperator          = errors.New("invalid operator")
+	ErrInvalidCredentials       = errors.New("invalid login or password")
+	ErrInvalidToken             = errors.New("invalid or expired token")
+	ErrInvalidFilter            = errors.New("invalid filter")
+	ErrNoIssuingOrganization    = errors.New("no issuing organization matches the application")
+	ErrRerouteNotAllowed        = errors.New("loan application can no longer be re-routed")
+	ErrInvalidSettings          = errors.New("invalid organization settings")
+	ErrSettingsVersionConflict  = errors.New("organization settings were changed by another request")
+	ErrSettingsVersionRequired  = errors.New("current settings version is required to update organization settings")
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+	ErrInvalidDedupPolicy       = errors.New("invalid dedup policy")
+	ErrInvalidWebhookEndpoint   = errors.New("invalid webhook endpoint")
+	ErrInvalidDeliveryFilter    = errors.New("invalid webhook delivery filter")
+	ErrInvalidImportFile        = errors.New("invalid import file")
+	ErrInvalidImportFilter      = errors.New("invalid import filter")
 )
+
+// DuplicateLoanApplicationError - заявка отклонена как дубль. Клиенту сообщается только UUID исходной заявки:
+// при scope global она может принадлежать другой организации.
+type DuplicateLoanApplicationError struct {
+	OriginalUUID uuid.UUID
+}
+
+func (e *DuplicateLoanApplicationError) Error() string {
+	return ErrDuplicateLoanApplication.Error()
+}
+
+func (e *DuplicateLoanApplicationError) Unwrap() error {
+	return ErrDuplicateLoanApplication
+}
+
+// ProblemExtensions - поля ответа с ошибкой, см. problem.FromError
+func (e *DuplicateLoanApplicationError) ProblemExtensions() map[string]interface{} {
+	return map[string]interface{}{"duplicate_of": e.OriginalUUID}
+}
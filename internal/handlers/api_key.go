@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+type HTTPAPIKeyHandler struct {
+	service domain.APIKeyService
+	logger  *slog.Logger
+}
+
+func NewHTTPAPIKeyHandler(service domain.APIKeyService, logger *slog.Logger) *HTTPAPIKeyHandler {
+	return &HTTPAPIKeyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetByOrganization возвращает ключи организации без секретов
+func (h *HTTPAPIKeyHandler) GetByOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	keys, err := h.service.GetByOrganization(ctx, organizationID)
+	if err != nil {
+		h.logger.Error("failed to get api keys", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, keys)
+}
+
+// Issue выпускает новый ключ, секрет возвращается только в этом ответе
+func (h *HTTPAPIKeyHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	key, err := h.service.Issue(ctx, organizationID, req.Name)
+	if err != nil {
+		h.logger.Error("failed to issue api key", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, key)
+}
+
+// Revoke отзывает ключ организации
+func (h *HTTPAPIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	keyID, ok := h.pathUUID(w, r, "key_uuid")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Revoke(ctx, organizationID, keyID); err != nil {
+		h.logger.Error("failed to revoke api key", slog.String("uuid", keyID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPAPIKeyHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	baseHandler := NewBaseHandler(h.logger)
+	return baseHandler.pathUUID(w, r, name)
+}
+
+func (h *HTTPAPIKeyHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleAPIKeyError(w, err)
+}
+
+func (h *HTTPAPIKeyHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPAPIKeyHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
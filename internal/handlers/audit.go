@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"app_aggregator/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type HTTPAuditHandler struct {
+	service domain.AuditService
+	logger  *slog.Logger
+}
+
+func NewHTTPAuditHandler(service domain.AuditService, logger *slog.Logger) *HTTPAuditHandler {
+	return &HTTPAuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// List возвращает журнал изменений, фильтры: entity, uuid, actor, limit, offset
+func (h *HTTPAuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := &domain.AuditFilter{
+		EntityType: query.Get("entity"),
+		Actor:      query.Get("actor"),
+	}
+
+	if uuidStr := query.Get("uuid"); uuidStr != "" {
+		id, err := uuid.Parse(uuidStr)
+		if err != nil {
+			h.logger.Error("invalid UUID format", slog.String("uuid", uuidStr), slog.String("error", err.Error()))
+			h.writeError(w, http.StatusBadRequest, "Invalid UUID format")
+			return
+		}
+		filter.EntityUUID = &id
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	entries, err := h.service.List(ctx, filter)
+	if err != nil {
+		h.logger.Error("failed to get audit log", slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *HTTPAuditHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleAuditError(w, err)
+}
+
+func (h *HTTPAuditHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPAuditHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
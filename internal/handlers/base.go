@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"app_aggregator/internal"
+	"app_aggregator/internal/problem"
+
+	"github.com/google/uuid"
 )
 
 type BaseHandler struct {
@@ -27,32 +31,169 @@ func (h *BaseHandler) writeJSON(w http.ResponseWriter, status int, data interfac
 	}
 }
 
+// writeError отвечает problem+json с типом по статусу, message идет в detail
 func (h *BaseHandler) writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+	h.writeProblemResponse(w, problem.New(status, message))
+}
+
+// writeProblem отвечает problem+json для ошибки сервиса. detail заменяет текст ошибки,
+// если обработчик может описать ее точнее.
+func (h *BaseHandler) writeProblem(w http.ResponseWriter, err error, detail string) {
+	p := problem.FromError(err)
+	if detail != "" && p.Status != http.StatusInternalServerError {
+		p.Detail = detail
+	}
+	h.writeProblemResponse(w, p)
+}
+
+// writeFieldError отвечает 400 validation-error с ошибкой поля field
+func (h *BaseHandler) writeFieldError(w http.ResponseWriter, field, message string) {
+	h.writeProblem(w, &problem.ValidationError{Errors: []problem.FieldError{{Field: field, Message: message}}}, "")
+}
 
-	errorResponse := map[string]string{"error": message}
-	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+func (h *BaseHandler) writeProblemResponse(w http.ResponseWriter, p *problem.Problem) {
+	if err := problem.Write(w, p); err != nil {
 		h.logger.Error("failed to encode error response", slog.String("error", err.Error()))
 	}
 }
 
+// pathUUID разбирает UUID из параметра пути name, при ошибке сам отвечает 400
+func (h *BaseHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	uuidStr := r.PathValue(name)
+	if uuidStr == "" {
+		h.logger.Error("missing UUID parameter", slog.String("parameter", name))
+		h.writeError(w, http.StatusBadRequest, "Missing UUID parameter")
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(uuidStr)
+	if err != nil {
+		h.logger.Error("invalid UUID format", slog.String("uuid", uuidStr), slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid UUID format")
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
 func (h *BaseHandler) handleOrganizationError(w http.ResponseWriter, err error) {
 	switch {
 	case err == internal.ErrRecordNoFound:
-		h.writeError(w, http.StatusNotFound, "Organization not found")
+		h.writeProblem(w, err, "Organization not found")
 	default:
-		h.writeError(w, http.StatusInternalServerError, "Internal server error")
+		h.writeProblem(w, err, "")
 	}
 }
 
 func (h *BaseHandler) handleLoanApplicationError(w http.ResponseWriter, err error) {
 	switch {
 	case err == internal.ErrRecordNoFound:
-		h.writeError(w, http.StatusNotFound, "Loan application not found")
-	case err == internal.ErrPhoneNumberExistToday:
-		h.writeError(w, http.StatusConflict, "Phone number already exists today")
+		h.writeProblem(w, err, "Loan application not found")
+	case errors.Is(err, internal.ErrDuplicateLoanApplication):
+		h.writeProblem(w, err, "Loan application with this phone already exists")
+	case err == internal.ErrNoIssuingOrganization:
+		h.writeProblem(w, err, "No issuing organization matches the application")
+	case err == internal.ErrInvalidStatus:
+		h.writeProblem(w, err, "Invalid status")
+	case err == internal.ErrIllegalTransition:
+		h.writeProblem(w, err, "Illegal status transition")
+	case err == internal.ErrInvalidFilter:
+		h.writeProblem(w, err, "Invalid filter")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleClientError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrInvalidPhoneNumber, err == internal.ErrEmptyPhoneNumber:
+		h.writeProblem(w, err, "Invalid phone number")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleAuditError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrInvalidAuditFilter:
+		h.writeProblem(w, err, "Invalid audit filter")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrRecordNoFound:
+		h.writeProblem(w, err, "API key or organization not found")
+	case err == internal.ErrInvalidAPIKeyName:
+		h.writeProblem(w, err, "Invalid API key name")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleOperatorError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrInvalidCredentials:
+		h.writeProblem(w, err, "Invalid login or password")
+	case err == internal.ErrInvalidOperator:
+		h.writeProblem(w, err, "Invalid operator: login, password of at least 8 characters and role are required")
+	case err == internal.ErrRecordNoFound:
+		h.writeError(w, http.StatusUnauthorized, "Invalid or expired token")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleSettingsError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrRecordNoFound:
+		h.writeProblem(w, err, "Organization or settings not found")
+	case err == internal.ErrInvalidSettings:
+		h.writeProblem(w, err, "Invalid settings: pdn must be between 0 and 80")
+	case err == internal.ErrSettingsVersionConflict:
+		h.writeProblem(w, err, "Settings were changed by another request, reload and retry")
+	case err == internal.ErrSettingsVersionRequired:
+		h.writeProblem(w, err, "Pass the current settings version in If-Match or the version field")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleDedupError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrRecordNoFound:
+		h.writeProblem(w, err, "Organization or loan application not found")
+	case err == internal.ErrInvalidDedupPolicy:
+		h.writeProblem(w, err, "Invalid dedup policy: window of 1 to 8760 hours, scope incoming_organization or global and action reject, merge or link are required")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrRecordNoFound:
+		h.writeProblem(w, err, "Webhook endpoint, delivery or organization not found")
+	case err == internal.ErrInvalidWebhookEndpoint:
+		h.writeProblem(w, err, "Invalid webhook endpoint: http(s) url and known event types are required")
+	case err == internal.ErrInvalidDeliveryFilter:
+		h.writeProblem(w, err, "Invalid webhook delivery filter")
+	default:
+		h.writeProblem(w, err, "")
+	}
+}
+
+func (h *BaseHandler) handleImportError(w http.ResponseWriter, err error) {
+	switch {
+	case err == internal.ErrRecordNoFound:
+		h.writeProblem(w, err, "Import job or organization not found")
+	case errors.Is(err, internal.ErrInvalidImportFile):
+		h.writeProblem(w, err, "")
+	case err == internal.ErrInvalidImportFilter:
+		h.writeProblem(w, err, "Invalid import filter")
 	default:
-		h.writeError(w, http.StatusInternalServerError, "Internal server error")
+		h.writeProblem(w, err, "")
 	}
 }
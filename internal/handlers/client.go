@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal/domain"
+	"app_aggregator/pkg/validators"
+)
+
+type HTTPClientHandler struct {
+	service domain.ClientService
+	logger  *slog.Logger
+}
+
+func NewHTTPClientHandler(service domain.ClientService, logger *slog.Logger) *HTTPClientHandler {
+	return &HTTPClientHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetHistory возвращает историю клиента по всем legacy базам
+func (h *HTTPClientHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	phone := r.PathValue("phone")
+	if !validators.ValidPhone(phone) {
+		h.logger.Error("invalid phone number", slog.String("phone", validators.MaskPhone(phone)))
+		h.writeFieldError(w, "phone", "invalid phone number format")
+		return
+	}
+
+	normalizedPhone, err := validators.PhoneNormalization(phone)
+	if err != nil {
+		h.logger.Error("failed to normalize phone number", slog.String("phone", validators.MaskPhone(phone)), slog.String("error", err.Error()))
+		h.writeFieldError(w, "phone", "invalid phone number")
+		return
+	}
+
+	histories, err := h.service.GetHistory(ctx, normalizedPhone)
+	if err != nil {
+		h.logger.Error("failed to get client history", slog.String("phone", validators.MaskPhone(normalizedPhone)), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, histories)
+}
+
+// GetSourcesHealth возвращает состояние legacy источников
+func (h *HTTPClientHandler) GetSourcesHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	health, err := h.service.GetSourcesHealth(ctx)
+	if err != nil {
+		h.logger.Error("failed to get client sources health", slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, health)
+}
+
+func (h *HTTPClientHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleClientError(w, err)
+}
+
+func (h *HTTPClientHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPClientHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
+
+func (h *HTTPClientHandler) writeFieldError(w http.ResponseWriter, field, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeFieldError(w, field, message)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+type HTTPDedupHandler struct {
+	service domain.DedupService
+	logger  *slog.Logger
+}
+
+func NewHTTPDedupHandler(service domain.DedupService, logger *slog.Logger) *HTTPDedupHandler {
+	return &HTTPDedupHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetPolicy возвращает политику дедупликации организации
+func (h *HTTPDedupHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.GetPolicy(ctx, organizationID)
+	if err != nil {
+		h.logger.Error("failed to get dedup policy", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, policy)
+}
+
+// SavePolicy задает политику дедупликации для заявок, поступающих от организации
+func (h *HTTPDedupHandler) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	var req SaveDedupPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+	if req.WindowHours != 0 && req.WindowDays != 0 {
+		h.writeError(w, http.StatusBadRequest, "Only one of window_hours and window_days can be set")
+		return
+	}
+
+	policy := &domain.DedupPolicy{
+		OrganizationUUID: organizationID,
+		WindowHours:      req.WindowHours + req.WindowDays*24,
+		Scope:            domain.DedupScope(req.Scope),
+		Action:           domain.DedupAction(req.Action),
+	}
+
+	savedPolicy, err := h.service.SavePolicy(ctx, policy)
+	if err != nil {
+		h.logger.Error("failed to save dedup policy", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, savedPolicy)
+}
+
+// DuplicateChain возвращает исходную заявку и все ее дубли
+func (h *HTTPDedupHandler) DuplicateChain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	chain, err := h.service.DuplicateChain(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get duplicate chain", slog.String("uuid", id.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, chain)
+}
+
+func (h *HTTPDedupHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	baseHandler := NewBaseHandler(h.logger)
+	return baseHandler.pathUUID(w, r, name)
+}
+
+func (h *HTTPDedupHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleDedupError(w, err)
+}
+
+func (h *HTTPDedupHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPDedupHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal/domain"
+)
+
+type HTTPHealthHandler struct {
+	service domain.HealthService
+	logger  *slog.Logger
+}
+
+func NewHTTPHealthHandler(service domain.HealthService, logger *slog.Logger) *HTTPHealthHandler {
+	return &HTTPHealthHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Live сообщает, что процесс запущен и обслуживает HTTP, без проверки зависимостей
+func (h *HTTPHealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Ready проверяет зависимости и отвечает 503, если недоступна обязательная
+func (h *HTTPHealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.service.Readiness(r.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+		for _, dependency := range report.Dependencies {
+			if dependency.Status == domain.DependencyDown {
+				h.logger.Warn("dependency is down",
+					slog.String("dependency", dependency.Name),
+					slog.Bool("required", dependency.Required),
+					slog.String("error", dependency.Error),
+				)
+			}
+		}
+	}
+
+	h.writeJSON(w, status, report)
+}
+
+func (h *HTTPHealthHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"app_aggregator/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxImportFileSize = 20 << 20
+	// importMemory - часть формы, которая держится в памяти, остальное пишется во временные файлы
+	importMemory = 8 << 20
+)
+
+type HTTPImportHandler struct {
+	service domain.ImportService
+	logger  *slog.Logger
+}
+
+func NewHTTPImportHandler(service domain.ImportService, logger *slog.Logger) *HTTPImportHandler {
+	return &HTTPImportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Import загружает заявки из CSV или XLSX файла (multipart поле file) от имени organization_uuid.
+// Поле mapping - JSON с заголовками колонок phone, value и comment.
+// Небольшие файлы обрабатываются сразу (201), большие и не успевшие обработаться за время запроса -
+// фоновой задачей (202), прогресс по Location.
+func (h *HTTPImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	if err := r.ParseMultipartForm(importMemory); err != nil {
+		h.logger.Error("failed to parse multipart form", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid multipart form or file larger than 20 MB")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	organizationID, err := uuid.Parse(r.FormValue("organization_uuid"))
+	if err != nil {
+		h.logger.Error("invalid organization UUID", slog.String("uuid", r.FormValue("organization_uuid")), slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid organization_uuid")
+		return
+	}
+
+	mapping := domain.DefaultImportColumnMapping()
+	if raw := r.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			h.logger.Error("invalid column mapping", slog.String("error", err.Error()))
+			h.writeError(w, http.StatusBadRequest, "Invalid mapping")
+			return
+		}
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.logger.Error("missing import file", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Missing file")
+		return
+	}
+	defer file.Close()
+
+	job, err := h.service.Start(ctx, organizationID, header.Filename, file, mapping)
+	if err != nil {
+		h.logger.Error("failed to import loan applications", slog.String("file", header.Filename), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if job.Status == domain.ImportJobStatusRunning {
+		status = http.StatusAccepted
+	}
+	w.Header().Set("Location", "/api/v1/admin/loan_applications/import/"+job.UUID.String())
+	h.writeJSON(w, status, job)
+}
+
+// GetJob возвращает состояние и счетчики задачи импорта
+func (h *HTTPImportHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(ctx, jobID)
+	if err != nil {
+		h.logger.Error("failed to get import job", slog.String("uuid", jobID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, job)
+}
+
+// ListRows возвращает построчный отчет задачи, фильтры: status, limit, offset
+func (h *HTTPImportHandler) ListRows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	jobID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	filter := &domain.ImportRowFilter{
+		Status: domain.ImportRowStatus(query.Get("status")),
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	rows, err := h.service.ListRows(ctx, jobID, filter)
+	if err != nil {
+		h.logger.Error("failed to get import rows", slog.String("uuid", jobID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, rows)
+}
+
+func (h *HTTPImportHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	baseHandler := NewBaseHandler(h.logger)
+	return baseHandler.pathUUID(w, r, name)
+}
+
+func (h *HTTPImportHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleImportError(w, err)
+}
+
+func (h *HTTPImportHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPImportHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
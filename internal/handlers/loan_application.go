@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
+	"app_aggregator/internal"
+	"app_aggregator/internal/auth"
 	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
 	"app_aggregator/pkg/validators"
 
 	"github.com/google/uuid"
@@ -26,14 +31,24 @@ func NewHTTPLoanApplicationHandler(service domain.LoanApplicationService, logger
 func (h *HTTPLoanApplicationHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	applications, err := h.service.GetAll(ctx)
+	filter, err := parseLoanApplicationFilter(r.URL.Query())
+	if err != nil {
+		h.logger.Error("invalid loan applications filter", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid filter: "+err.Error())
+		return
+	}
+
+	page, err := h.service.GetAll(ctx, filter)
 	if err != nil {
 		h.logger.Error("failed to get loan applications", slog.String("error", err.Error()))
 		h.handleError(w, err)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, applications)
+	for i, app := range page.Items {
+		page.Items[i] = presentLoanApplication(ctx, app)
+	}
+	h.writeJSON(w, http.StatusOK, page)
 }
 
 func (h *HTTPLoanApplicationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -60,7 +75,7 @@ func (h *HTTPLoanApplicationHandler) GetByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, application)
+	h.writeJSON(w, http.StatusOK, presentLoanApplication(ctx, application))
 }
 
 func (h *HTTPLoanApplicationHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -72,25 +87,31 @@ func (h *HTTPLoanApplicationHandler) Create(w http.ResponseWriter, r *http.Reque
 		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
 
-	if !validators.ValidPhone(req.Phone) {
-		h.logger.Error("invalid phone number", slog.String("phone", req.Phone))
-		h.writeError(w, http.StatusBadRequest, "Invalid phone number format")
+	organization, ok := auth.OrganizationFromContext(ctx)
+	if !ok {
+		h.logger.Error("missing authenticated organization")
+		h.writeError(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	normalizedPhone, err := validators.PhoneNormalization(req.Phone)
+	phone, err := validators.ParsePhone(req.Phone, organization.AllowedPhoneCountries...)
 	if err != nil {
-		h.logger.Error("failed to normalize phone number", slog.String("phone", req.Phone), slog.String("error", err.Error()))
-		h.writeError(w, http.StatusBadRequest, "Invalid phone number")
+		h.logger.Error("failed to normalize phone number", slog.String("phone", validators.MaskPhone(req.Phone)), slog.String("error", err.Error()))
+		h.writeFieldError(w, "phone", err.Error())
 		return
 	}
 
 	app := &domain.LoanApplication{
-		IncomingOrganizationName: req.IncomingOrganizationName,
-		IssueOrganizationName:    req.IssueOrganizationName,
+		IncomingOrganizationName: organization.Name,
 		Value:                    req.Value,
-		Phone:                    normalizedPhone,
+		Phone:                    phone.E164,
+		PhoneCountry:             phone.Country,
+		PhoneType:                string(phone.Type),
 		Comment:                  req.Comment,
 	}
 
@@ -101,7 +122,11 @@ func (h *HTTPLoanApplicationHandler) Create(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	h.writeJSON(w, http.StatusCreated, createdApp)
+	status := http.StatusCreated
+	if createdApp.Merged {
+		status = http.StatusOK
+	}
+	h.writeJSON(w, status, presentLoanApplication(ctx, createdApp))
 }
 
 func (h *HTTPLoanApplicationHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -127,41 +152,84 @@ func (h *HTTPLoanApplicationHandler) Update(w http.ResponseWriter, r *http.Reque
 		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
-
-	// Валидация и нормализация телефона (если передан)
-	var normalizedPhone string
-	if req.Phone != "" {
-		if !validators.ValidPhone(req.Phone) {
-			h.logger.Error("invalid phone number", slog.String("phone", req.Phone))
-			h.writeError(w, http.StatusBadRequest, "Invalid phone number format")
-			return
-		}
-
-		var err error
-		normalizedPhone, err = validators.PhoneNormalization(req.Phone)
-		if err != nil {
-			h.logger.Error("failed to normalize phone number", slog.String("phone", req.Phone), slog.String("error", err.Error()))
-			h.writeError(w, http.StatusBadRequest, "Invalid phone number")
-			return
-		}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
 	}
 
 	app := &domain.LoanApplication{
 		IncomingOrganizationName: req.IncomingOrganizationName,
-		IssueOrganizationName:    req.IssueOrganizationName,
 		Value:                    req.Value,
-		Phone:                    normalizedPhone,
 		Comment:                  req.Comment,
 	}
 
+	// Нормализация телефона (если передан), страны входящей организации проверяет сервис
+	if req.Phone != "" {
+		phone, err := validators.ParsePhone(req.Phone)
+		if err != nil {
+			h.logger.Error("failed to normalize phone number", slog.String("phone", validators.MaskPhone(req.Phone)), slog.String("error", err.Error()))
+			h.writeFieldError(w, "phone", err.Error())
+			return
+		}
+		app.Phone = phone.E164
+		app.PhoneCountry = phone.Country
+		app.PhoneType = string(phone.Type)
+	}
+
 	updatedApp, err := h.service.Update(ctx, id, app)
+	if errors.Is(err, internal.ErrPhoneCountryNotAllowed) {
+		h.writeFieldError(w, "phone", err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to update loan application", slog.String("uuid", id.String()), slog.String("error", err.Error()))
 		h.handleError(w, err)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, updatedApp)
+	h.writeJSON(w, http.StatusOK, presentLoanApplication(ctx, updatedApp))
+}
+
+func (h *HTTPLoanApplicationHandler) Transition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	uuidStr := r.PathValue("uuid")
+	if uuidStr == "" {
+		h.logger.Error("missing UUID parameter")
+		h.writeError(w, http.StatusBadRequest, "Missing UUID parameter")
+		return
+	}
+
+	id, err := uuid.Parse(uuidStr)
+	if err != nil {
+		h.logger.Error("invalid UUID format", slog.String("uuid", uuidStr), slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid UUID format")
+		return
+	}
+
+	var req TransitionLoanApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	updatedApp, err := h.service.Transition(ctx, id, domain.LoanApplicationStatus(req.Status))
+	if err != nil {
+		h.logger.Error("failed to transition loan application",
+			slog.String("uuid", id.String()),
+			slog.String("status", req.Status),
+			slog.String("error", err.Error()),
+		)
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, presentLoanApplication(ctx, updatedApp))
 }
 
 func (h *HTTPLoanApplicationHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -190,6 +258,14 @@ func (h *HTTPLoanApplicationHandler) Delete(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// presentLoanApplication скрывает телефон, если у вызывающего нет права pii:read
+func presentLoanApplication(ctx context.Context, app *domain.LoanApplication) *domain.LoanApplication {
+	if auth.CanFromContext(ctx, auth.PermissionPIIRead) {
+		return app
+	}
+	return app.Masked()
+}
+
 func (h *HTTPLoanApplicationHandler) handleError(w http.ResponseWriter, err error) {
 	baseHandler := NewBaseHandler(h.logger)
 	baseHandler.handleLoanApplicationError(w, err)
@@ -204,3 +280,8 @@ func (h *HTTPLoanApplicationHandler) writeError(w http.ResponseWriter, status in
 	baseHandler := NewBaseHandler(h.logger)
 	baseHandler.writeError(w, status, message)
 }
+
+func (h *HTTPLoanApplicationHandler) writeFieldError(w http.ResponseWriter, field, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeFieldError(w, field, message)
+}
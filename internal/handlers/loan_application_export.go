@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/pkg/validators"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	// exportFlushEvery - через сколько строк ответ сбрасывается клиенту и продлевается дедлайн записи
+	exportFlushEvery   = 500
+	exportWriteTimeout = time.Minute
+)
+
+// exportColumns - доступные колонки выгрузки
+var exportColumns = map[string]func(app *domain.LoanApplication) interface{}{
+	"uuid":                       func(app *domain.LoanApplication) interface{} { return app.UUID.String() },
+	"incoming_organization_name": func(app *domain.LoanApplication) interface{} { return app.IncomingOrganizationName },
+	"issue_organization_name":    func(app *domain.LoanApplication) interface{} { return app.IssueOrganizationName },
+	"value":                      func(app *domain.LoanApplication) interface{} { return app.Value },
+	"phone":                      func(app *domain.LoanApplication) interface{} { return app.Phone },
+	"phone_country":              func(app *domain.LoanApplication) interface{} { return app.PhoneCountry },
+	"phone_type":                 func(app *domain.LoanApplication) interface{} { return app.PhoneType },
+	"comment":                    func(app *domain.LoanApplication) interface{} { return app.Comment },
+	"status":                     func(app *domain.LoanApplication) interface{} { return string(app.Status) },
+	"routing_reason":             func(app *domain.LoanApplication) interface{} { return app.RoutingReason },
+	"unknown_sources":            func(app *domain.LoanApplication) interface{} { return strings.Join(app.UnknownSources, ",") },
+	"duplicate_of_uuid": func(app *domain.LoanApplication) interface{} {
+		if app.DuplicateOfUUID == nil {
+			return nil
+		}
+		return app.DuplicateOfUUID.String()
+	},
+	"created_at": func(app *domain.LoanApplication) interface{} { return app.CreatedAt.Format(time.RFC3339) },
+	"updated_at": func(app *domain.LoanApplication) interface{} { return app.UpdatedAt.Format(time.RFC3339) },
+}
+
+var defaultExportColumns = []string{
+	"uuid", "incoming_organization_name", "issue_organization_name", "value", "phone", "status", "created_at",
+}
+
+// exportOptions - формат, колонки и маскирование телефона выгрузки
+type exportOptions struct {
+	format    string
+	columns   []string
+	maskPhone bool
+}
+
+// parseExportOptions разбирает format (csv или ndjson), columns (через запятую) и mask_phone (по умолчанию true)
+func parseExportOptions(query url.Values) (*exportOptions, error) {
+	options := &exportOptions{
+		format:    query.Get("format"),
+		columns:   defaultExportColumns,
+		maskPhone: true,
+	}
+
+	switch options.format {
+	case "":
+		options.format = exportFormatCSV
+	case exportFormatCSV, exportFormatNDJSON:
+	default:
+		return nil, errors.New("invalid format")
+	}
+
+	if columns := query.Get("columns"); columns != "" {
+		options.columns = nil
+		for _, column := range strings.Split(columns, ",") {
+			column = strings.TrimSpace(column)
+			if _, ok := exportColumns[column]; !ok {
+				return nil, errors.New("unknown column " + column)
+			}
+			options.columns = append(options.columns, column)
+		}
+	}
+
+	if maskPhone := query.Get("mask_phone"); maskPhone != "" {
+		var err error
+		if options.maskPhone, err = strconv.ParseBool(maskPhone); err != nil {
+			return nil, errors.New("invalid mask_phone")
+		}
+	}
+
+	return options, nil
+}
+
+// Export выгружает заявки в CSV или NDJSON потоком. Фильтры те же, что у списка, limit не учитывается.
+func (h *HTTPLoanApplicationHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseLoanApplicationFilter(r.URL.Query())
+	if err != nil {
+		h.logger.Error("invalid loan applications filter", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid filter: "+err.Error())
+		return
+	}
+	options, err := parseExportOptions(r.URL.Query())
+	if err != nil {
+		h.logger.Error("invalid export options", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid export options: "+err.Error())
+		return
+	}
+	if !options.maskPhone && !auth.CanFromContext(ctx, auth.PermissionPIIRead) {
+		h.writeError(w, http.StatusForbidden, "mask_phone=false requires the pii:read permission")
+		return
+	}
+
+	writer := newExportWriter(w, options)
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to extend export write deadline", slog.String("error", err.Error()))
+	}
+	exported := 0
+
+	err = h.service.Export(ctx, filter, func(app *domain.LoanApplication) error {
+		if err := writer.write(app); err != nil {
+			return err
+		}
+		exported++
+		if exported%exportFlushEvery == 0 {
+			return flushExport(controller, writer)
+		}
+		return nil
+	})
+	if err != nil {
+		if !writer.started {
+			h.logger.Error("failed to export loan applications", slog.String("error", err.Error()))
+			h.handleError(w, err)
+			return
+		}
+		// заголовки уже отправлены, поэтому клиент увидит оборванный ответ
+		h.logger.Error("loan applications export interrupted", slog.Int("exported", exported), slog.String("error", err.Error()))
+		return
+	}
+
+	if err := writer.finish(); err != nil {
+		h.logger.Error("failed to finish loan applications export", slog.String("error", err.Error()))
+	}
+}
+
+func flushExport(controller *http.ResponseController, writer *exportWriter) error {
+	if err := writer.flush(); err != nil {
+		return err
+	}
+	if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if err := controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// exportWriter пишет строки выгрузки, заголовки ответа отправляются с первой строкой,
+// чтобы ошибку фильтра еще можно было вернуть обычным ответом
+type exportWriter struct {
+	w       http.ResponseWriter
+	options *exportOptions
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+}
+
+func newExportWriter(w http.ResponseWriter, options *exportOptions) *exportWriter {
+	return &exportWriter{
+		w:       w,
+		options: options,
+	}
+}
+
+func (e *exportWriter) start() error {
+	e.started = true
+
+	extension, contentType := "csv", "text/csv; charset=utf-8"
+	if e.options.format == exportFormatNDJSON {
+		extension, contentType = "ndjson", "application/x-ndjson"
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="loan_applications_%s.%s"`,
+		time.Now().Format("20060102_150405"), extension))
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.options.format == exportFormatNDJSON {
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	e.csv = csv.NewWriter(e.w)
+	return e.csv.Write(e.options.columns)
+}
+
+func (e *exportWriter) write(app *domain.LoanApplication) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	if e.json != nil {
+		record := make(map[string]interface{}, len(e.options.columns))
+		for _, column := range e.options.columns {
+			record[column] = e.value(column, app)
+		}
+		return e.json.Encode(record)
+	}
+
+	record := make([]string, len(e.options.columns))
+	for i, column := range e.options.columns {
+		switch value := e.value(column, app).(type) {
+		case nil:
+		case string:
+			record[i] = csvCell(value)
+		default:
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return e.csv.Write(record)
+}
+
+// csvCell экранирует значения, которые табличный редактор выполнит как формулу:
+// comment и названия организаций задают партнеры
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *exportWriter) value(column string, app *domain.LoanApplication) interface{} {
+	if column == "phone" && e.options.maskPhone {
+		return validators.MaskPhone(app.Phone)
+	}
+	return exportColumns[column](app)
+}
+
+func (e *exportWriter) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// finish отправляет остаток буфера, для пустой выгрузки - только заголовок CSV
+func (e *exportWriter) finish() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
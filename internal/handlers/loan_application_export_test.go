@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http/httptest"
+	"testing"
+
+	"app_aggregator/internal/domain"
+)
+
+func TestExportWriterEscapesFormulas(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer := newExportWriter(recorder, &exportOptions{
+		format:  exportFormatCSV,
+		columns: []string{"incoming_organization_name", "comment", "value", "phone"},
+	})
+
+	apps := []*domain.LoanApplication{
+		{IncomingOrganizationName: "=HYPERLINK(\"http://evil\")", Comment: "+1+1", Value: 1000, Phone: "+79123456789"},
+		{IncomingOrganizationName: "@SUM(A1)", Comment: "-2", Value: 2000},
+		{IncomingOrganizationName: "\tcmd", Comment: "\r=1"},
+		{IncomingOrganizationName: "Альфа", Comment: "a=b"},
+	}
+	for _, app := range apps {
+		if err := writer.write(app); err != nil {
+			t.Fatalf("write() error = %v", err)
+		}
+	}
+	if err := writer.finish(); err != nil {
+		t.Fatalf("finish() error = %v", err)
+	}
+
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	want := [][]string{
+		{"incoming_organization_name", "comment", "value", "phone"},
+		{"'=HYPERLINK(\"http://evil\")", "'+1+1", "1000", "'+79123456789"},
+		{"'@SUM(A1)", "'-2", "2000", ""},
+		{"'\tcmd", "'\r=1", "0", ""},
+		{"Альфа", "a=b", "0", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("record[%d][%d] = %q, want %q", i, j, records[i][j], want[i][j])
+			}
+		}
+	}
+}
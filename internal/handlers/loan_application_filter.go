@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	"app_aggregator/internal/domain"
+	"app_aggregator/pkg/validators"
+
+	"github.com/google/uuid"
+)
+
+// parseLoanApplicationFilter разбирает параметры запроса списка заявок.
+// Даты принимаются в формате RFC3339 или YYYY-MM-DD, created_to не включается в диапазон.
+func parseLoanApplicationFilter(query url.Values) (*domain.LoanApplicationFilter, error) {
+	filter := &domain.LoanApplicationFilter{
+		Status: domain.LoanApplicationStatus(query.Get("status")),
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+		Cursor: query.Get("cursor"),
+	}
+
+	var err error
+	if filter.IncomingOrganizationUUID, err = parseUUIDParam(query, "incoming_organization_uuid"); err != nil {
+		return nil, err
+	}
+	if filter.IssueOrganizationUUID, err = parseUUIDParam(query, "issue_organization_uuid"); err != nil {
+		return nil, err
+	}
+	if filter.MinValue, err = parseInt64Param(query, "min_value"); err != nil {
+		return nil, err
+	}
+	if filter.MaxValue, err = parseInt64Param(query, "max_value"); err != nil {
+		return nil, err
+	}
+	if filter.CreatedFrom, err = parseTimeParam(query, "created_from"); err != nil {
+		return nil, err
+	}
+	if filter.CreatedTo, err = parseTimeParam(query, "created_to"); err != nil {
+		return nil, err
+	}
+
+	if phone := query.Get("phone"); phone != "" {
+		if filter.Phone, err = validators.PhoneNormalization(phone); err != nil {
+			return nil, errors.New("invalid phone")
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, errors.New("invalid limit")
+		}
+	}
+
+	return filter, nil
+}
+
+func parseUUIDParam(query url.Values, name string) (*uuid.UUID, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, errors.New("invalid " + name)
+	}
+	return &id, nil
+}
+
+func parseInt64Param(query url.Values, name string) (*int64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid " + name)
+	}
+	return &number, nil
+}
+
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New("invalid " + name)
+	}
+	return &t, nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
+)
+
+type HTTPOperatorHandler struct {
+	service domain.OperatorService
+	logger  *slog.Logger
+}
+
+func NewHTTPOperatorHandler(service domain.OperatorService, logger *slog.Logger) *HTTPOperatorHandler {
+	return &HTTPOperatorHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Login выдает токен оператора по логину и паролю
+func (h *HTTPOperatorHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	token, err := h.service.Login(ctx, req.Login, req.Password)
+	if err != nil {
+		h.logger.Warn("failed to login", slog.String("login", req.Login), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, token)
+}
+
+// Logout отзывает текущий токен оператора
+func (h *HTTPOperatorHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token, ok := auth.BearerToken(r)
+	if !ok {
+		h.writeError(w, http.StatusUnauthorized, "Missing bearer token")
+		return
+	}
+
+	if err := h.service.Logout(ctx, token); err != nil {
+		h.logger.Error("failed to logout", slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPOperatorHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	operators, err := h.service.GetAll(ctx)
+	if err != nil {
+		h.logger.Error("failed to get operators", slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, operators)
+}
+
+func (h *HTTPOperatorHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateOperatorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	operator, err := h.service.Create(ctx, req.Login, req.Password, domain.OperatorRole(req.Role))
+	if err != nil {
+		h.logger.Error("failed to create operator", slog.String("login", req.Login), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, operator)
+}
+
+func (h *HTTPOperatorHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleOperatorError(w, err)
+}
+
+func (h *HTTPOperatorHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPOperatorHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
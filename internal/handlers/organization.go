@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
 
 	"github.com/google/uuid"
 )
@@ -73,9 +74,14 @@ func (h *HTTPOrganizationHandler) Create(w http.ResponseWriter, r *http.Request)
 		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
 
 	org := &domain.Organization{
-		Name: req.Name,
+		Name:                  req.Name,
+		AllowedPhoneCountries: req.AllowedPhoneCountries,
 	}
 
 	createdOrg, err := h.service.Create(ctx, org)
@@ -111,9 +117,14 @@ func (h *HTTPOrganizationHandler) Update(w http.ResponseWriter, r *http.Request)
 		h.writeError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
 
 	org := &domain.Organization{
-		Name: req.Name,
+		Name:                  req.Name,
+		AllowedPhoneCountries: req.AllowedPhoneCountries,
 	}
 
 	updatedOrg, err := h.service.Update(ctx, id, org)
@@ -1,25 +1,72 @@
 package handlers
 
+// CreateOrganizationRequest - пустой allowed_phone_countries разрешает номера всех поддерживаемых стран
 type CreateOrganizationRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name                  string   `json:"name" validate:"required,min=2,max=150,name"`
+	AllowedPhoneCountries []string `json:"allowed_phone_countries" validate:"dive,phone_country"`
 }
 
+// UpdateOrganizationRequest - без allowed_phone_countries список стран не меняется
 type UpdateOrganizationRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name                  string   `json:"name" validate:"required,min=2,max=150,name"`
+	AllowedPhoneCountries []string `json:"allowed_phone_countries" validate:"omitempty,dive,phone_country"`
 }
 
+// CreateLoanApplicationRequest не содержит входящую организацию: она определяется по API ключу
 type CreateLoanApplicationRequest struct {
-	IncomingOrganizationName string `json:"incoming_organization_name" validate:"required"`
-	IssueOrganizationName    string `json:"issue_organization_name" validate:"required"`
-	Value                    int64  `json:"value" validate:"required"`
-	Phone                    string `json:"phone" validate:"required"`
-	Comment                  string `json:"comment"`
+	Value   int64  `json:"value" validate:"required,min=1000"`
+	Phone   string `json:"phone" validate:"required,phone"`
+	Comment string `json:"comment"`
 }
 
+// UpdateLoanApplicationRequest - пустые поля не меняются. Организация-выдача выбирается
+// маршрутизацией, поэтому issue_organization_name принимается только пустым.
 type UpdateLoanApplicationRequest struct {
-	IncomingOrganizationName string `json:"incoming_organization_name"`
-	IssueOrganizationName    string `json:"issue_organization_name"`
-	Value                    int64  `json:"value"`
-	Phone                    string `json:"phone"`
+	IncomingOrganizationName string `json:"incoming_organization_name" validate:"omitempty,min=2,max=150,name"`
+	IssueOrganizationName    string `json:"issue_organization_name" validate:"isdefault"`
+	Value                    int64  `json:"value" validate:"omitempty,min=1000"`
+	Phone                    string `json:"phone" validate:"omitempty,phone"`
 	Comment                  string `json:"comment"`
 }
+
+type TransitionLoanApplicationRequest struct {
+	Status string `json:"status" validate:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type LoginRequest struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type CreateOperatorRequest struct {
+	Login    string `json:"login" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+	Role     string `json:"role" validate:"required,oneof=viewer operator admin"`
+}
+
+// SaveDedupPolicyRequest - окно задается либо в часах, либо в днях
+type SaveDedupPolicyRequest struct {
+	WindowHours int    `json:"window_hours" validate:"required_without=WindowDays,omitempty,min=1"`
+	WindowDays  int    `json:"window_days" validate:"required_without=WindowHours,omitempty,min=1"`
+	Scope       string `json:"scope" validate:"required,oneof=incoming_organization global"`
+	Action      string `json:"action" validate:"required,oneof=reject merge link"`
+}
+
+// CreateWebhookEndpointRequest - пустой event_types означает подписку на все события
+type CreateWebhookEndpointRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types" validate:"dive,required"`
+}
+
+// SaveSettingsRequest - все поля обязательны, version - текущая версия настроек для защиты от
+// одновременного изменения, ее можно передать в If-Match. Без версии настройки можно только создать.
+type SaveSettingsRequest struct {
+	NewClient *bool  `json:"new_client" validate:"required"`
+	PDN       *int64 `json:"pdn" validate:"required,min=0,max=80"`
+	HasDebt   *bool  `json:"has_debt" validate:"required"`
+	Version   int    `json:"version" validate:"omitempty,min=1"`
+}
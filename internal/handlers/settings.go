@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+type HTTPSettingsHandler struct {
+	service domain.SettingsService
+	logger  *slog.Logger
+}
+
+func NewHTTPSettingsHandler(service domain.SettingsService, logger *slog.Logger) *HTTPSettingsHandler {
+	return &HTTPSettingsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Get возвращает текущие настройки организации, версия дублируется в ETag
+func (h *HTTPSettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	settings, err := h.service.GetByOrganization(ctx, organizationID)
+	if err != nil {
+		h.logger.Error("failed to get settings", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", settingsETag(settings.Version))
+	h.writeJSON(w, http.StatusOK, settings)
+}
+
+// Save заменяет настройки организации новой версией. Для изменения существующих настроек
+// нужна текущая версия в If-Match или поле version, иначе 428; устаревшая версия - 409.
+func (h *HTTPSettingsHandler) Save(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	var req SaveSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	version := req.Version
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		matched, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid If-Match header")
+			return
+		}
+		// одна из версий устарела
+		if version != 0 && version != matched {
+			h.handleError(w, internal.ErrSettingsVersionConflict)
+			return
+		}
+		version = matched
+	}
+
+	settings := &domain.Settings{
+		OrganizationUUID: organizationID,
+		NewClient:        *req.NewClient,
+		PDN:              *req.PDN,
+		HasDebt:          *req.HasDebt,
+		Version:          version,
+	}
+
+	saved, err := h.service.Save(ctx, settings)
+	if err != nil {
+		h.logger.Error("failed to save settings", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", settingsETag(saved.Version))
+	h.writeJSON(w, http.StatusOK, saved)
+}
+
+// Versions возвращает историю настроек организации
+func (h *HTTPSettingsHandler) Versions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	versions, err := h.service.Versions(ctx, organizationID)
+	if err != nil {
+		h.logger.Error("failed to get settings versions", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, versions)
+}
+
+func settingsETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func (h *HTTPSettingsHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	baseHandler := NewBaseHandler(h.logger)
+	return baseHandler.pathUUID(w, r, name)
+}
+
+func (h *HTTPSettingsHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleSettingsError(w, err)
+}
+
+func (h *HTTPSettingsHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPSettingsHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
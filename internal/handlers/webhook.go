@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/validation"
+
+	"github.com/google/uuid"
+)
+
+type HTTPWebhookHandler struct {
+	service domain.WebhookService
+	logger  *slog.Logger
+}
+
+func NewHTTPWebhookHandler(service domain.WebhookService, logger *slog.Logger) *HTTPWebhookHandler {
+	return &HTTPWebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetEndpoints возвращает webhook адреса организации без секретов
+func (h *HTTPWebhookHandler) GetEndpoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.service.GetEndpoints(ctx, organizationID)
+	if err != nil {
+		h.logger.Error("failed to get webhook endpoints", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, endpoints)
+}
+
+// CreateEndpoint регистрирует адрес, секрет подписи возвращается только в этом ответе
+func (h *HTTPWebhookHandler) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	var req CreateWebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("failed to decode request body", slog.String("error", err.Error()))
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validation.Struct(&req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	endpoint, err := h.service.CreateEndpoint(ctx, organizationID, req.URL, req.EventTypes)
+	if err != nil {
+		h.logger.Error("failed to create webhook endpoint", slog.String("organization_uuid", organizationID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, endpoint)
+}
+
+// DeleteEndpoint удаляет адрес, неотправленные события на него переходят в dead
+func (h *HTTPWebhookHandler) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	endpointID, ok := h.pathUUID(w, r, "webhook_uuid")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEndpoint(ctx, organizationID, endpointID); err != nil {
+		h.logger.Error("failed to delete webhook endpoint", slog.String("uuid", endpointID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries возвращает доставки, фильтры: status, organization_uuid, limit, offset.
+// status=dead - очередь недоставленных событий.
+func (h *HTTPWebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := &domain.WebhookDeliveryFilter{
+		Status: query.Get("status"),
+	}
+
+	if uuidStr := query.Get("organization_uuid"); uuidStr != "" {
+		id, err := uuid.Parse(uuidStr)
+		if err != nil {
+			h.logger.Error("invalid UUID format", slog.String("uuid", uuidStr), slog.String("error", err.Error()))
+			h.writeError(w, http.StatusBadRequest, "Invalid UUID format")
+			return
+		}
+		filter.OrganizationUUID = &id
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil {
+			h.writeError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	deliveries, err := h.service.ListDeliveries(ctx, filter)
+	if err != nil {
+		h.logger.Error("failed to get webhook deliveries", slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, deliveries)
+}
+
+// RetryDelivery возвращает dead доставку в очередь отправки
+func (h *HTTPWebhookHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deliveryID, ok := h.pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.RetryDelivery(ctx, deliveryID)
+	if err != nil {
+		h.logger.Error("failed to retry webhook delivery", slog.String("uuid", deliveryID.String()), slog.String("error", err.Error()))
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, delivery)
+}
+
+func (h *HTTPWebhookHandler) pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	baseHandler := NewBaseHandler(h.logger)
+	return baseHandler.pathUUID(w, r, name)
+}
+
+func (h *HTTPWebhookHandler) handleError(w http.ResponseWriter, err error) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.handleWebhookError(w, err)
+}
+
+func (h *HTTPWebhookHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeJSON(w, status, data)
+}
+
+func (h *HTTPWebhookHandler) writeError(w http.ResponseWriter, status int, message string) {
+	baseHandler := NewBaseHandler(h.logger)
+	baseHandler.writeError(w, status, message)
+}
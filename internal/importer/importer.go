@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// DetectFormat определяет формат по расширению файла
+func DetectFormat(fileName string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv", ".txt":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", fmt.Errorf("%w: unsupported file type %q, csv or xlsx expected", internal.ErrInvalidImportFile, filepath.Ext(fileName))
+}
+
+// Parse читает строки заявок из файла. Первая строка - заголовок, колонки ищутся по mapping
+// без учета регистра. Пустые строки пропускаются. Файл с более чем maxRows строками отклоняется.
+func Parse(r io.Reader, format Format, mapping domain.ImportColumnMapping, maxRows int) ([]domain.ImportRow, error) {
+	var records [][]string
+	var err error
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatXLSX:
+		records, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", internal.ErrInvalidImportFile, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", internal.ErrInvalidImportFile)
+	}
+
+	columns, err := resolveColumns(records[0], mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []domain.ImportRow
+	for n, record := range records[1:] {
+		if blank(record) {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d rows", internal.ErrInvalidImportFile, maxRows)
+		}
+		rows = append(rows, domain.ImportRow{
+			Line:    n + 2,
+			Phone:   cell(record, columns.phone),
+			Value:   cell(record, columns.value),
+			Comment: cell(record, columns.comment),
+		})
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows after header", internal.ErrInvalidImportFile)
+	}
+
+	return rows, nil
+}
+
+// readCSV понимает разделители "," и ";" (выгрузки Excel с русской локалью) и UTF-8 BOM
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := bufio.NewReader(r)
+	if bom, err := reader.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		_, _ = reader.Discard(len(utf8BOM))
+	}
+
+	header, err := reader.Peek(reader.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	header, _, _ = bytes.Cut(header, []byte("\n"))
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		csvReader.Comma = ';'
+	}
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", internal.ErrInvalidImportFile, err.Error())
+	}
+	return records, nil
+}
+
+// readXLSX читает первый лист книги
+func readXLSX(r io.Reader) ([][]string, error) {
+	book, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", internal.ErrInvalidImportFile, err.Error())
+	}
+	defer book.Close()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("%w: workbook has no sheets", internal.ErrInvalidImportFile)
+	}
+
+	records, err := book.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", internal.ErrInvalidImportFile, err.Error())
+	}
+	return records, nil
+}
+
+type columnIndexes struct {
+	phone   int
+	value   int
+	comment int
+}
+
+// resolveColumns находит колонки по заголовку. Колонка комментария необязательна.
+func resolveColumns(header []string, mapping domain.ImportColumnMapping) (*columnIndexes, error) {
+	find := func(name string) int {
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	columns := &columnIndexes{
+		phone:   find(mapping.Phone),
+		value:   find(mapping.Value),
+		comment: -1,
+	}
+	if columns.phone < 0 {
+		return nil, fmt.Errorf("%w: phone column %q not found", internal.ErrInvalidImportFile, mapping.Phone)
+	}
+	if columns.value < 0 {
+		return nil, fmt.Errorf("%w: value column %q not found", internal.ErrInvalidImportFile, mapping.Value)
+	}
+	if mapping.Comment != "" {
+		columns.comment = find(mapping.Comment)
+	}
+
+	return columns, nil
+}
+
+func cell(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+func blank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
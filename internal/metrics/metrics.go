@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "app_aggregator"
+
+// Результаты проверки rate limiter
+const (
+	RateLimitAllowed = "allowed"
+	RateLimitBlocked = "blocked"
+)
+
+// Результаты попытки доставки webhook
+const (
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+	WebhookDead      = "dead"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RateLimitRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rate_limit",
+		Name:      "requests_total",
+		Help:      "Requests checked by the rate limiter by decision (allowed, blocked).",
+	}, []string{"decision"})
+
+	ClientSourceLookupDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "client_source",
+		Name:      "lookup_duration_seconds",
+		Help:      "Client lookup latency in legacy lender databases by source and status (found, not_found, unknown).",
+		Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"source", "status"})
+
+	ClientSourceLookupErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "client_source",
+		Name:      "lookup_errors_total",
+		Help:      "Failed client lookups in legacy lender databases by source and reason (timeout, circuit_open, error).",
+	}, []string{"source", "reason"})
+
+	ClientSourceCircuitState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "client_source",
+		Name:      "circuit_state",
+		Help:      "Circuit breaker state of a legacy lender database: 0 closed, 1 half-open, 2 open.",
+	}, []string{"source"})
+
+	LoanApplicationsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "loan_applications",
+		Name:      "created_total",
+		Help:      "Created loan applications by incoming and issuing organization.",
+	}, []string{"incoming_organization", "issue_organization"})
+
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Webhook delivery attempts by event type and result (delivered, failed, dead).",
+	}, []string{"event_type", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RateLimitRequests,
+		ClientSourceLookupDuration,
+		ClientSourceLookupErrors,
+		ClientSourceCircuitState,
+		LoanApplicationsCreated,
+		WebhookDeliveries,
+	)
+}
+
+// Handler отдает метрики в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal"
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/problem"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type OperatorAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.Operator, error)
+}
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*domain.Organization, error)
+}
+
+// APIKeyAuth определяет организацию-партнера по заголовку X-API-Key
+func APIKeyAuth(authenticator APIKeyAuthenticator, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				writeAuthError(w, http.StatusUnauthorized, "Missing API key")
+				return
+			}
+
+			organization, err := authenticator.Authenticate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, internal.ErrInvalidAPIKey) {
+					logger.Warn("Invalid API key",
+						slog.String("ip", getIP(r)),
+						slog.String("method", r.Method),
+						slog.String("path", redactPath(r.URL.Path)),
+					)
+					writeAuthError(w, http.StatusUnauthorized, "Invalid API key")
+					return
+				}
+				logger.Error("failed to authenticate API key", slog.String("error", err.Error()))
+				writeAuthError(w, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+
+			ctx := auth.WithOrganization(r.Context(), organization)
+			ctx = auth.WithActor(ctx, "organization:"+organization.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OperatorAuth определяет оператора по заголовку Authorization: Bearer <token>
+func OperatorAuth(authenticator OperatorAuthenticator, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := auth.BearerToken(r)
+			if !ok {
+				writeAuthError(w, http.StatusUnauthorized, "Missing bearer token")
+				return
+			}
+
+			operator, err := authenticator.Authenticate(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, internal.ErrInvalidToken) {
+					logger.Warn("Invalid operator token",
+						slog.String("ip", getIP(r)),
+						slog.String("method", r.Method),
+						slog.String("path", redactPath(r.URL.Path)),
+					)
+					writeAuthError(w, http.StatusUnauthorized, "Invalid or expired token")
+					return
+				}
+				logger.Error("failed to authenticate operator", slog.String("error", err.Error()))
+				writeAuthError(w, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+
+			ctx := auth.WithOperator(r.Context(), operator)
+			ctx = auth.WithActor(ctx, "operator:"+operator.Login)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequirePermission пропускает запрос, только если роль оператора дает право permission.
+// Должен стоять после OperatorAuth.
+func RequirePermission(permission auth.Permission, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			operator, ok := auth.OperatorFromContext(r.Context())
+			if !ok {
+				writeAuthError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			if !auth.HasPermission(operator.Role, permission) {
+				logger.Warn("Permission denied",
+					slog.String("operator", operator.Login),
+					slog.String("role", string(operator.Role)),
+					slog.String("permission", string(permission)),
+					slog.String("method", r.Method),
+					slog.String("path", redactPath(r.URL.Path)),
+				)
+				writeAuthError(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeAuthError(w http.ResponseWriter, status int, message string) {
+	problem.Write(w, problem.New(status, message))
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"app_aggregator/internal"
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/problem"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// Idempotency сохраняет успешный ответ на запрос с заголовком Idempotency-Key и
+// возвращает его же при повторе. Тот же ключ с другим телом запроса дает 422.
+// Ключи разделены по организациям, поэтому middleware должен стоять после APIKeyAuth.
+func Idempotency(service domain.IdempotencyService, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			organization, ok := auth.OrganizationFromContext(r.Context())
+			if !ok || organization.UUID == nil {
+				writeAuthError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+			if err != nil {
+				writeAuthError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			record, err := service.Begin(r.Context(), *organization.UUID, key, requestHash(r, body))
+			if err != nil {
+				writeIdempotencyError(w, err, logger)
+				return
+			}
+			if record != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.ResponseBody)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// ответ уже отправлен клиенту, сохраняем его даже если клиент отключился
+			ctx := context.WithoutCancel(r.Context())
+			if recorder.statusCode >= 200 && recorder.statusCode < 300 {
+				err = service.Complete(ctx, *organization.UUID, key, recorder.statusCode, recorder.body.Bytes())
+			} else {
+				err = service.Release(ctx, *organization.UUID, key)
+			}
+			if err != nil {
+				logger.Error("failed to store idempotent response",
+					slog.String("organization", organization.Name),
+					slog.String("error", err.Error()),
+				)
+			}
+		})
+	}
+}
+
+// requestHash не зависит от форматирования JSON и порядка полей в теле запроса
+func requestHash(r *http.Request, body []byte) string {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if canonical, err := json.Marshal(payload); err == nil {
+			body = canonical
+		}
+	}
+
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeIdempotencyError(w http.ResponseWriter, err error, logger *slog.Logger) {
+	resp := problem.FromError(err)
+	switch {
+	case errors.Is(err, internal.ErrInvalidIdempotencyKey):
+		resp.Detail = "Idempotency-Key must be 1 to 255 characters long"
+	case errors.Is(err, internal.ErrIdempotencyKeyReused):
+		resp.Detail = "Idempotency-Key was already used with a different request"
+	case errors.Is(err, internal.ErrIdempotencyKeyInProgress):
+		w.Header().Set("Retry-After", "1")
+		resp.Detail = "Request with this Idempotency-Key is still in progress"
+	default:
+		logger.Error("failed to check idempotency key", slog.String("error", err.Error()))
+	}
+	problem.Write(w, resp)
+}
+
+// responseRecorder пишет ответ клиенту и одновременно запоминает его
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	r.statusCode = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
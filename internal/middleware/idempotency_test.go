@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestHash(t *testing.T) {
+	base := requestHash(httptest.NewRequest("POST", "/loan_applications", nil), []byte(`{"phone":"+79123456789","value":1000}`))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		same   bool
+	}{
+		{"same request", "POST", "/loan_applications", `{"phone":"+79123456789","value":1000}`, true},
+		{"formatting", "POST", "/loan_applications", "{\n  \"phone\": \"+79123456789\",\n  \"value\": 1000\n}", true},
+		{"field order", "POST", "/loan_applications", `{"value":1000,"phone":"+79123456789"}`, true},
+		{"query string", "POST", "/loan_applications?x=1", `{"phone":"+79123456789","value":1000}`, true},
+		{"different body", "POST", "/loan_applications", `{"phone":"+79123456789","value":2000}`, false},
+		{"different path", "POST", "/loan_applications/import", `{"phone":"+79123456789","value":1000}`, false},
+		{"different method", "PUT", "/loan_applications", `{"phone":"+79123456789","value":1000}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestHash(httptest.NewRequest(tt.method, tt.path, nil), []byte(tt.body))
+			if (got == base) != tt.same {
+				t.Errorf("requestHash() same = %v, want %v", got == base, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"app_aggregator/internal/metrics"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unmatchedRoute - метка для запросов, не попавших ни в один маршрут,
+// чтобы произвольные пути не раздували число временных рядов
+const unmatchedRoute = "unmatched"
+
+// Metrics считает запросы и их длительность по маршруту.
+// Должен оборачивать ServeMux напрямую: маршрут берется из r.Pattern,
+// который ServeMux заполняет у переданного ему запроса.
+func Metrics() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapped := &ResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			defer func() {
+				err := recover()
+				status := wrapped.statusCode
+				if err != nil {
+					status = http.StatusInternalServerError
+				}
+
+				route := unmatchedRoute
+				if r.Pattern != "" {
+					route = r.Pattern
+					if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+						route = path
+					}
+				}
+
+				labels := []string{r.Method, route, strconv.Itoa(status)}
+				metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+				metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+				// паника обрабатывается в Recovery
+				if err != nil {
+					panic(err)
+				}
+			}()
+
+			next.ServeHTTP(wrapped, r)
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package middleware
 
 import (
-	"encoding/json"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"runtime/debug"
 	"time"
+
+	"app_aggregator/internal/problem"
+	"app_aggregator/internal/requestid"
+	"app_aggregator/pkg/validators"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ResponseWriter struct {
@@ -18,6 +24,23 @@ func (rw *ResponseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap дает http.ResponseController доступ к Flush и дедлайнам исходного ResponseWriter
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// clientHistoryPathRe - маршрут с телефоном клиента в пути
+var clientHistoryPathRe = regexp.MustCompile(`^(/api/v1/clients/)([^/]+)(/history/?)$`)
+
+// redactPath маскирует телефон в пути запроса, чтобы он не попадал в логи и спаны
+func redactPath(path string) string {
+	match := clientHistoryPathRe.FindStringSubmatch(path)
+	if match == nil {
+		return path
+	}
+	return match[1] + validators.MaskPhone(match[2]) + match[3]
+}
+
 func Chain(handler http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
 	for i := len(middlewares) - 1; i >= 0; i-- {
 		handler = middlewares[i](handler)
@@ -36,8 +59,10 @@ func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 
 			duration := time.Since(start).Milliseconds()
 			logger.Info("HTTP request",
+				slog.String("trace_id", trace.SpanContextFromContext(r.Context()).TraceID().String()),
+				slog.String("request_id", requestid.FromContext(r.Context())),
 				slog.String("method", r.Method),
-				slog.String("path", r.URL.Path),
+				slog.String("path", redactPath(r.URL.Path)),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.Int("status_code", wrapped.statusCode),
@@ -52,7 +77,8 @@ func CORS() func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, Idempotency-Key, If-Match, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag, Location, Retry-After, Idempotent-Replayed")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -73,14 +99,10 @@ func Recovery(logger *slog.Logger) func(http.Handler) http.Handler {
 						slog.Any("error", err),
 						slog.String("stack", string(debug.Stack())),
 						slog.String("method", r.Method),
-						slog.String("path", r.URL.Path),
+						slog.String("path", redactPath(r.URL.Path)),
 					)
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					json.NewEncoder(w).Encode(map[string]string{
-						"error": "Internal server error",
-					})
+					problem.Write(w, problem.New(http.StatusInternalServerError, ""))
 				}
 			}()
 
@@ -1,8 +1,9 @@
 package middleware
 
 import (
+	"app_aggregator/internal/metrics"
+	"app_aggregator/internal/problem"
 	"app_aggregator/internal/ratelimit"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net"
@@ -17,35 +18,36 @@ func RateLimitWithLogger(cfg *ratelimit.Config, logger *slog.Logger) func(http.H
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ip := getIP(r)
 			if !limiter.IsAllowed(ip) {
+				metrics.RateLimitRequests.WithLabelValues(metrics.RateLimitBlocked).Inc()
 				logger.Warn("Request blocked by rate limiter",
 					slog.String("ip", ip),
 					slog.String("method", r.Method),
-					slog.String("path", r.URL.Path),
+					slog.String("path", redactPath(r.URL.Path)),
 					slog.String("user_agent", r.UserAgent()),
 				)
 
-				w.Header().Set("Content-Type", "application/json")
 				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(cfg.BlockDuration.Seconds())))
-				w.WriteHeader(http.StatusTooManyRequests)
 
-				resp := map[string]interface{}{
-					"error":       "Rate limit exceeded",
+				resp := problem.New(http.StatusTooManyRequests, "Rate limit exceeded")
+				resp.Extensions = map[string]interface{}{
 					"retry_after": int(cfg.BlockDuration.Seconds()),
 					"limit":       cfg.RequestsPerMinute,
 					"window_size": cfg.WindowSize.String(),
 					"stats":       limiter.GetStats(),
 					"ip":          ip,
 				}
-				json.NewEncoder(w).Encode(resp)
+				problem.Write(w, resp)
 				return
 			}
 
+			metrics.RateLimitRequests.WithLabelValues(metrics.RateLimitAllowed).Inc()
+
 			remaining := limiter.GetRemaining(ip)
 			if remaining <= 10 {
 				logger.Warn("Rate limit is close to the limit",
 					slog.String("ip", ip),
 					slog.String("method", r.Method),
-					slog.String("path", r.URL.Path),
+					slog.String("path", redactPath(r.URL.Path)),
 					slog.Int("remaining", remaining),
 				)
 			}
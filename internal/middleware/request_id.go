@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"app_aggregator/internal/requestid"
+	"net/http"
+)
+
+// RequestID принимает X-Request-ID клиента или генерирует свой, кладет его в контекст
+// и заголовок ответа. Ответы с ошибкой берут идентификатор из заголовка ответа.
+// Должен стоять перед Tracing: Tracing читает r.Pattern из того же *http.Request,
+// который получает ServeMux, поэтому после него запрос нельзя заменять через WithContext.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestid.New(r.Header.Get(requestid.Header))
+
+			w.Header().Set(requestid.Header, id)
+
+			next.ServeHTTP(w, r.WithContext(requestid.WithRequestID(r.Context(), id)))
+		})
+	}
+}
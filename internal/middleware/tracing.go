@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"app_aggregator/internal/requestid"
+	"app_aggregator/internal/tracing"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing открывает серверный спан на запрос, продолжая трейс из заголовков traceparent.
+// Имя спана уточняется маршрутом после того, как его выберет ServeMux.
+func Tracing() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(redactPath(r.URL.Path)),
+					attribute.String("request_id", requestid.FromContext(r.Context())),
+				),
+			)
+			defer span.End()
+
+			wrapped := &ResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			r = r.WithContext(ctx)
+
+			next.ServeHTTP(wrapped, r)
+
+			if r.Pattern != "" {
+				span.SetName(r.Pattern)
+				span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(wrapped.statusCode))
+			if wrapped.statusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(wrapped.statusCode))
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APIKey struct {
+	gorm.Model
+	UUID             *uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	OrganizationUUID *uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Organization     Organization `gorm:"foreignKey:OrganizationUUID;references:UUID"`
+	Name             string       `gorm:"type:varchar(255);not null"`
+	Prefix           string       `gorm:"type:varchar(16);not null"`
+	KeyHash          string       `gorm:"type:char(64);not null;uniqueIndex"`
+	LastUsedAt       *time.Time
+	RevokedAt        *time.Time
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog - append-only журнал изменений сущностей
+type AuditLog struct {
+	ID         uint       `gorm:"primarykey"`
+	EntityType string     `gorm:"type:varchar(50);not null;index:idx_audit_logs_entity"`
+	EntityUUID *uuid.UUID `gorm:"type:uuid;not null;index:idx_audit_logs_entity"`
+	Actor      string     `gorm:"type:varchar(255);not null;index"`
+	Action     string     `gorm:"type:varchar(50);not null"`
+	Before     *string    `gorm:"type:jsonb"`
+	After      *string    `gorm:"type:jsonb"`
+	Diff       *string    `gorm:"type:jsonb"`
+	CreatedAt  time.Time  `gorm:"not null;index"`
+}
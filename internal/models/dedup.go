@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DedupPolicy struct {
+	ID               uint         `gorm:"primarykey"`
+	OrganizationUUID *uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex"`
+	Organization     Organization `gorm:"foreignKey:OrganizationUUID;references:UUID"`
+	WindowHours      int          `gorm:"not null;check:window_hours > 0"`
+	Scope            string       `gorm:"type:varchar(30);not null"`
+	Action           string       `gorm:"type:varchar(20);not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// LoanApplicationDuplicate - обнаруженный дубль заявки. DuplicateUUID пустой,
+// если новая заявка не создавалась (отклонена или слита с исходной).
+type LoanApplicationDuplicate struct {
+	ID                       uint         `gorm:"primarykey"`
+	OriginalUUID             *uuid.UUID   `gorm:"type:uuid;not null;index"`
+	DuplicateUUID            *uuid.UUID   `gorm:"type:uuid"`
+	IncomingOrganizationUUID *uuid.UUID   `gorm:"type:uuid;not null"`
+	IncomingOrganization     Organization `gorm:"foreignKey:IncomingOrganizationUUID;references:UUID"`
+	Action                   string       `gorm:"type:varchar(20);not null"`
+	CreatedAt                time.Time
+}
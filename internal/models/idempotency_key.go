@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey - сохраненный ответ на запрос с заголовком Idempotency-Key.
+// StatusCode 0 означает, что запрос еще выполняется.
+type IdempotencyKey struct {
+	ID               uint       `gorm:"primarykey"`
+	OrganizationUUID *uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_organization_key"`
+	Key              string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_organization_key"`
+	RequestHash      string     `gorm:"type:char(64);not null"`
+	StatusCode       int        `gorm:"not null;default:0"`
+	ResponseBody     []byte
+	CreatedAt        time.Time
+	ExpiresAt        time.Time `gorm:"index"`
+}
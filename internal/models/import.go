@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportJob struct {
+	ID               uint         `gorm:"primarykey"`
+	UUID             *uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	OrganizationUUID *uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Organization     Organization `gorm:"foreignKey:OrganizationUUID;references:UUID"`
+	FileName         string       `gorm:"type:text;not null"`
+	Status           string       `gorm:"type:varchar(20);not null"`
+	TotalRows        int          `gorm:"not null"`
+	ProcessedRows    int          `gorm:"not null;default:0"`
+	Created          int          `gorm:"not null;default:0"`
+	Duplicates       int          `gorm:"not null;default:0"`
+	Invalid          int          `gorm:"not null;default:0"`
+	Failed           int          `gorm:"not null;default:0"`
+	Error            string       `gorm:"type:text"`
+	Actor            string       `gorm:"type:varchar(100);not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	FinishedAt       *time.Time
+}
+
+type ImportJobRow struct {
+	ID                  uint       `gorm:"primarykey"`
+	JobID               uint       `gorm:"not null;uniqueIndex:idx_import_job_rows_job_line"`
+	Line                int        `gorm:"not null;uniqueIndex:idx_import_job_rows_job_line"`
+	Status              string     `gorm:"type:varchar(20);not null"`
+	Reason              string     `gorm:"type:text"`
+	LoanApplicationUUID *uuid.UUID `gorm:"type:uuid"`
+}
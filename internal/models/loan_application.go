@@ -14,5 +14,14 @@ type LoanApplication struct {
 	IssueOrganization        Organization `gorm:"foreignKey:IssueOrganizationUuid;references:UUID"`
 	Value                    int64        `gorm:"not null;check:value >= 1000"`
 	Phone                    string       `gorm:"not null;size:20"`
+	PhoneEncrypted           string       `gorm:"type:text"`
+	PhoneHash                string       `gorm:"type:varchar(64);index"`
+	PhoneCountry             string       `gorm:"type:varchar(2)"`
+	PhoneType                string       `gorm:"type:varchar(10)"`
 	Comment                  string       `gorm:"type:text"`
+	Status                   string       `gorm:"type:varchar(20);not null;default:'new';index"`
+	RoutingReason            string       `gorm:"type:text"`
+	UnknownSources           string       `gorm:"type:text"`
+	DuplicateOfUUID          *uuid.UUID   `gorm:"type:uuid;index"`
+	SettingsVersion          *int
 }
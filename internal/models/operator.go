@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Operator struct {
+	gorm.Model
+	UUID         *uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	Login        string     `gorm:"type:varchar(100);not null;uniqueIndex"`
+	PasswordHash string     `gorm:"type:varchar(100);not null"`
+	Role         string     `gorm:"type:varchar(20);not null"`
+	Disabled     bool       `gorm:"not null;default:false"`
+}
+
+type OperatorToken struct {
+	ID           uint       `gorm:"primarykey"`
+	OperatorUUID *uuid.UUID `gorm:"type:uuid;not null;index"`
+	Operator     Operator   `gorm:"foreignKey:OperatorUUID;references:UUID"`
+	TokenHash    string     `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt    time.Time  `gorm:"not null"`
+	RevokedAt    *time.Time
+	CreatedAt    time.Time
+}
@@ -7,6 +7,7 @@ import (
 
 type Organization struct {
 	gorm.Model
-	UUID *uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
-	Name string     `gorm:"type:varchar(255);not null;uniqueIndex" validate:"required,min=2,max=150,name"`
+	UUID                  *uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	Name                  string     `gorm:"type:varchar(255);not null;uniqueIndex" validate:"required,min=2,max=150,name"`
+	AllowedPhoneCountries string     `gorm:"type:text;not null;default:''"`
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -13,4 +15,17 @@ type Settings struct {
 	NewClient        bool         `gorm:"default:false" validate:"omitempty"`
 	PDN              int64        `gorm:"default:0;check:pdn>=0 AND pdn <= 80" validate:"min=0,max=80"`
 	HasDebt          bool         `gorm:"default:false;" validate:"omitempty"`
+	Version          int          `gorm:"not null;default:1"`
+}
+
+// SettingsVersion - снимок настроек организации, сохраняется при каждом изменении
+type SettingsVersion struct {
+	ID               uint       `gorm:"primarykey"`
+	OrganisationUUID *uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_settings_versions_organisation_version"`
+	Version          int        `gorm:"not null;uniqueIndex:idx_settings_versions_organisation_version"`
+	NewClient        bool       `gorm:"not null"`
+	PDN              int64      `gorm:"not null"`
+	HasDebt          bool       `gorm:"not null"`
+	Actor            string     `gorm:"type:varchar(100);not null"`
+	CreatedAt        time.Time
 }
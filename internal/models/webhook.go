@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookEndpoint struct {
+	gorm.Model
+	UUID             *uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	OrganizationUUID *uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Organization     Organization `gorm:"foreignKey:OrganizationUUID;references:UUID"`
+	URL              string       `gorm:"type:text;not null"`
+	Secret           string       `gorm:"type:varchar(64);not null"`
+	EventTypes       string       `gorm:"type:text"`
+}
+
+// OutboxEvent - доменное событие, записанное в одной транзакции с изменением заявки
+type OutboxEvent struct {
+	ID         uint       `gorm:"primarykey"`
+	UUID       *uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	EventType  string     `gorm:"type:varchar(50);not null"`
+	EntityUUID *uuid.UUID `gorm:"type:uuid;not null;index"`
+	Payload    string     `gorm:"type:jsonb;not null"`
+	CreatedAt  time.Time
+}
+
+type WebhookDelivery struct {
+	ID             uint            `gorm:"primarykey"`
+	UUID           *uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();uniqueIndex"`
+	EventID        uint            `gorm:"not null"`
+	Event          OutboxEvent     `gorm:"foreignKey:EventID"`
+	EndpointID     uint            `gorm:"not null"`
+	Endpoint       WebhookEndpoint `gorm:"foreignKey:EndpointID"`
+	Status         string          `gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts       int             `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time       `gorm:"not null"`
+	LastStatusCode *int
+	LastError      *string `gorm:"type:text"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,110 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Cipher шифрует персональные данные AES-256-GCM и считает для них слепой индекс HMAC-SHA256.
+// Шифротекст хранится как "<id ключа>:<base64(nonce|ciphertext)>", поэтому после смены текущего
+// ключа старые записи читаются прежними ключами, пока их не перешифруют.
+type Cipher struct {
+	keys     map[string]cipher.AEAD
+	current  string
+	indexKey []byte
+}
+
+// NewCipher - keys: id -> ключ AES-256 (32 байта), current - id ключа для новых записей
+func NewCipher(keys map[string][]byte, current string, indexKey []byte) (*Cipher, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, current)
+	}
+	if len(indexKey) < 32 {
+		return nil, errors.New("index key must be at least 32 bytes")
+	}
+
+	c := &Cipher{
+		keys:     make(map[string]cipher.AEAD, len(keys)),
+		current:  current,
+		indexKey: indexKey,
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	aead := c.keys[c.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return c.current + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Decrypt(value string) (string, error) {
+	id, encoded, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", errors.New("invalid encrypted value")
+	}
+	aead, ok := c.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Index - слепой индекс для поиска по равенству без расшифровки
+func (c *Cipher) Index(plaintext string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(plaintext))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted сообщает, что value получено из Encrypt: в телефонах и масках нет ':'
+func IsEncrypted(value string) bool {
+	return strings.Contains(value, ":")
+}
+
+// CurrentKeyPrefix - начало значений, зашифрованных текущим ключом
+func (c *Cipher) CurrentKeyPrefix() string {
+	return c.current + ":"
+}
@@ -0,0 +1,160 @@
+package pii
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestCipherEncryptDecrypt(t *testing.T) {
+	c, err := NewCipher(map[string][]byte{"v1": testKey(1)}, "v1", testKey(9))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	encrypted, err := c.Encrypt("+79123456789")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(encrypted, c.CurrentKeyPrefix()) {
+		t.Errorf("Encrypt() = %q, want prefix %q", encrypted, c.CurrentKeyPrefix())
+	}
+	if strings.Contains(encrypted, "9123456789") {
+		t.Errorf("Encrypt() = %q contains plaintext", encrypted)
+	}
+
+	again, err := c.Encrypt("+79123456789")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if again == encrypted {
+		t.Error("Encrypt() returned the same ciphertext twice, nonce is not random")
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if decrypted != "+79123456789" {
+		t.Errorf("Decrypt() = %q, want %q", decrypted, "+79123456789")
+	}
+}
+
+func TestCipherKeyRotation(t *testing.T) {
+	old, err := NewCipher(map[string][]byte{"v1": testKey(1)}, "v1", testKey(9))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	encryptedOld, err := old.Encrypt("+79123456789")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	rotated, err := NewCipher(map[string][]byte{"v1": testKey(1), "v2": testKey(2)}, "v2", testKey(9))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	decrypted, err := rotated.Decrypt(encryptedOld)
+	if err != nil || decrypted != "+79123456789" {
+		t.Fatalf("Decrypt(old) = %q, %v, want value encrypted with the previous key", decrypted, err)
+	}
+
+	encryptedNew, err := rotated.Encrypt("+79123456789")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(encryptedNew, "v2:") {
+		t.Errorf("Encrypt() = %q, want current key v2", encryptedNew)
+	}
+
+	if _, err := old.Decrypt(encryptedNew); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() with removed key error = %v, want ErrUnknownKey", err)
+	}
+	if old.Index("+79123456789") != rotated.Index("+79123456789") {
+		t.Error("Index() changed after encryption key rotation")
+	}
+}
+
+func TestCipherDecryptInvalid(t *testing.T) {
+	c, err := NewCipher(map[string][]byte{"v1": testKey(1)}, "v1", testKey(9))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	encrypted, err := c.Encrypt("+79123456789")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	tampered := []byte(encrypted)
+	tampered[len(tampered)-2] ^= 1
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"no key id", "plaintext"},
+		{"unknown key", "v9:" + strings.TrimPrefix(encrypted, "v1:")},
+		{"not base64", "v1:%%%"},
+		{"too short", "v1:AAAA"},
+		{"tampered", string(tampered)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Decrypt(tt.value); err == nil {
+				t.Errorf("Decrypt(%q) error = nil", tt.value)
+			}
+		})
+	}
+}
+
+func TestCipherIndex(t *testing.T) {
+	c, err := NewCipher(map[string][]byte{"v1": testKey(1)}, "v1", testKey(9))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	other, err := NewCipher(map[string][]byte{"v1": testKey(1)}, "v1", testKey(8))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	index := c.Index("+79123456789")
+	if len(index) != 64 {
+		t.Errorf("Index() length = %d, want 64", len(index))
+	}
+	if c.Index("+79123456789") != index {
+		t.Error("Index() is not deterministic")
+	}
+	if c.Index("+79123456780") == index {
+		t.Error("Index() is the same for different phones")
+	}
+	if other.Index("+79123456789") == index {
+		t.Error("Index() does not depend on the index key")
+	}
+}
+
+func TestNewCipherInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     map[string][]byte
+		current  string
+		indexKey []byte
+	}{
+		{"unknown current key", map[string][]byte{"v1": testKey(1)}, "v2", testKey(9)},
+		{"short key", map[string][]byte{"v1": testKey(1)[:16]}, "v1", testKey(9)},
+		{"key id with colon", map[string][]byte{"v:1": testKey(1)}, "v:1", testKey(9)},
+		{"short index key", map[string][]byte{"v1": testKey(1)}, "v1", testKey(9)[:16]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCipher(tt.keys, tt.current, tt.indexKey); err == nil {
+				t.Error("NewCipher() error = nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package problem
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/requestid"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ContentType - тип ответа с ошибкой по RFC 7807
+const ContentType = "application/problem+json"
+
+// typePrefix - типы ошибок задаются относительными URI, описание типа лежит в документации API
+const typePrefix = "/problems/"
+
+// Problem - тело ответа с ошибкой по RFC 7807.
+// Extensions добавляются в объект верхнего уровня рядом со стандартными полями.
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	Errors     []FieldError           `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// FieldError - ошибка конкретного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError - ошибка проверки запроса сразу по всем полям
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// New создает проблему с типом по HTTP статусу, например /problems/not-found
+func New(status int, detail string) *Problem {
+	title := http.StatusText(status)
+	return &Problem{
+		Type:   typePrefix + strings.ReplaceAll(strings.ToLower(title), " ", "-"),
+		Title:  title,
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// extender - ошибка с дополнительными полями ответа, например UUID исходной заявки для дубля
+type extender interface {
+	ProblemExtensions() map[string]interface{}
+}
+
+type kind struct {
+	err    error
+	status int
+	slug   string
+	title  string
+}
+
+// domainErrors - ошибки из internal/errors.go и их представление в API.
+// Текст ошибки безопасен для клиента и идет в detail.
+var domainErrors = []kind{
+	{internal.ErrRecordNoFound, http.StatusNotFound, "not-found", "Resource not found"},
+	{internal.ErrPhoneFormat, http.StatusBadRequest, "invalid-phone", "Invalid phone number"},
+	{internal.ErrInvalidPhoneNumber, http.StatusBadRequest, "invalid-phone", "Invalid phone number"},
+	{internal.ErrEmptyPhoneNumber, http.StatusBadRequest, "invalid-phone", "Invalid phone number"},
+	{internal.ErrPhoneCountryNotAllowed, http.StatusUnprocessableEntity, "phone-country-not-allowed", "Phone country not allowed"},
+	{internal.ErrDuplicateLoanApplication, http.StatusConflict, "duplicate-loan-application", "Duplicate loan application"},
+	{internal.ErrInvalidOrganizationName, http.StatusBadRequest, "invalid-organization", "Invalid organization"},
+	{internal.ErrInvalidPhoneCountries, http.StatusBadRequest, "invalid-organization", "Invalid organization"},
+	{internal.ErrInvalidLoanApplication, http.StatusBadRequest, "invalid-loan-application", "Invalid loan application"},
+	{internal.ErrClientSourceExists, http.StatusConflict, "client-source-exists", "Client source already registered"},
+	{internal.ErrInvalidStatus, http.StatusBadRequest, "invalid-status", "Invalid loan application status"},
+	{internal.ErrIllegalTransition, http.StatusConflict, "illegal-transition", "Illegal status transition"},
+	{internal.ErrInvalidAuditFilter, http.StatusBadRequest, "invalid-filter", "Invalid filter"},
+	{internal.ErrInvalidAPIKey, http.StatusUnauthorized, "invalid-api-key", "Invalid API key"},
+	{internal.ErrInvalidAPIKeyName, http.StatusBadRequest, "invalid-api-key-name", "Invalid API key name"},
+	{internal.ErrInvalidOperator, http.StatusBadRequest, "invalid-operator", "Invalid operator"},
+	{internal.ErrInvalidCredentials, http.StatusUnauthorized, "invalid-credentials", "Invalid credentials"},
+	{internal.ErrInvalidToken, http.StatusUnauthorized, "invalid-token", "Invalid token"},
+	{internal.ErrInvalidFilter, http.StatusBadRequest, "invalid-filter", "Invalid filter"},
+	{internal.ErrNoIssuingOrganization, http.StatusUnprocessableEntity, "no-issuing-organization", "No issuing organization"},
+	{internal.ErrRerouteNotAllowed, http.StatusConflict, "reroute-not-allowed", "Re-routing not allowed"},
+	{internal.ErrInvalidSettings, http.StatusBadRequest, "invalid-settings", "Invalid settings"},
+	{internal.ErrSettingsVersionConflict, http.StatusConflict, "settings-version-conflict", "Settings version conflict"},
+	{internal.ErrSettingsVersionRequired, http.StatusPreconditionRequired, "settings-version-required", "Settings version required"},
+	{internal.ErrInvalidIdempotencyKey, http.StatusBadRequest, "invalid-idempotency-key", "Invalid Idempotency-Key"},
+	{internal.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency-key-reused", "Idempotency-Key reused"},
+	{internal.ErrIdempotencyKeyInProgress, http.StatusConflict, "idempotency-key-in-progress", "Request in progress"},
+	{internal.ErrInvalidDedupPolicy, http.StatusBadRequest, "invalid-dedup-policy", "Invalid dedup policy"},
+	{internal.ErrInvalidWebhookEndpoint, http.StatusBadRequest, "invalid-webhook-endpoint", "Invalid webhook endpoint"},
+	{internal.ErrInvalidDeliveryFilter, http.StatusBadRequest, "invalid-filter", "Invalid filter"},
+	{internal.ErrInvalidImportFile, http.StatusBadRequest, "invalid-import-file", "Invalid import file"},
+	{internal.ErrInvalidImportFilter, http.StatusBadRequest, "invalid-filter", "Invalid filter"},
+}
+
+// postgresErrors - нарушения ограничений Postgres, которые вызваны данными запроса, а не сбоем
+var postgresErrors = map[string]kind{
+	"23505": {status: http.StatusConflict, slug: "unique-violation", title: "Resource already exists"},
+	"23503": {status: http.StatusBadRequest, slug: "foreign-key-violation", title: "Referenced resource does not exist"},
+	"23514": {status: http.StatusConflict, slug: "check-violation", title: "Constraint violated"},
+}
+
+// postgresConstraints - поля запроса, которые проверяют ограничения из migrations/sql.
+// Текст ошибки Postgres содержит имена таблиц и колонок и клиенту не отдается.
+var postgresConstraints = map[string]FieldError{
+	"chk_loan_applications_value":                {Field: "value", Message: "must be at least 1000"},
+	"chk_settings_pdn":                           {Field: "pdn", Message: "must be between 0 and 80"},
+	"chk_dedup_policies_window_hours":            {Field: "window_hours", Message: "must be greater than 0"},
+	"chk_dedup_policies_scope":                   {Field: "scope", Message: "is not a valid scope"},
+	"chk_dedup_policies_action":                  {Field: "action", Message: "is not a valid action"},
+	"idx_organizations_name":                     {Field: "name", Message: "is already taken"},
+	"idx_operators_login":                        {Field: "login", Message: "is already taken"},
+	"fk_loan_applications_incoming_organization": {Field: "incoming_organization_name", Message: "organization does not exist"},
+	"fk_loan_applications_issue_organization":    {Field: "issue_organization_name", Message: "organization does not exist"},
+	"fk_settings_organization":                   {Field: "organization_uuid", Message: "organization does not exist"},
+	"fk_dedup_policies_organization":             {Field: "organization_uuid", Message: "organization does not exist"},
+	"fk_api_keys_organization":                   {Field: "organization_uuid", Message: "organization does not exist"},
+	"fk_webhook_endpoints_organization":          {Field: "organization_uuid", Message: "organization does not exist"},
+	"fk_import_jobs_organization":                {Field: "organization_uuid", Message: "organization does not exist"},
+}
+
+// FromError переводит ошибку в проблему: доменные ошибки, ошибки проверки запроса
+// и нарушения ограничений Postgres. Остальные ошибки - 500 без подробностей.
+// Исходный текст ошибок Postgres в ответ не попадает, его логирует вызывающий.
+func FromError(err error) *Problem {
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		p := newKind(http.StatusBadRequest, "validation-error", "Validation failed")
+		p.Detail = "request has invalid fields"
+		p.Errors = validationError.Errors
+		return p
+	}
+
+	for _, known := range domainErrors {
+		if errors.Is(err, known.err) {
+			p := newKind(known.status, known.slug, known.title)
+			p.Detail = err.Error()
+			var ext extender
+			if errors.As(err, &ext) {
+				p.Extensions = ext.ProblemExtensions()
+			}
+			return p
+		}
+	}
+
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) {
+		if known, ok := postgresErrors[pgError.Code]; ok {
+			p := newKind(known.status, known.slug, known.title)
+			if fieldError, ok := postgresConstraints[pgError.ConstraintName]; ok {
+				p.Detail = "request has invalid fields"
+				p.Errors = []FieldError{fieldError}
+			}
+			return p
+		}
+	}
+
+	return New(http.StatusInternalServerError, "")
+}
+
+func newKind(status int, slug, title string) *Problem {
+	return &Problem{
+		Type:   typePrefix + slug,
+		Title:  title,
+		Status: status,
+	}
+}
+
+// Write отправляет проблему клиенту. Идентификатор запроса берется из заголовка ответа,
+// который выставляет middleware.RequestID, поэтому Write можно вызывать без *http.Request.
+func Write(w http.ResponseWriter, p *Problem) error {
+	if p.RequestID == "" {
+		p.RequestID = w.Header().Get(requestid.Header)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// MarshalJSON добавляет Extensions к стандартным полям, не давая их перезаписать
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	data, err := json.Marshal((*plain)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	fields := make(map[string]json.RawMessage, len(p.Extensions))
+	for name, value := range p.Extensions {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = raw
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,71 @@
+package problem
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFromErrorHidesPostgresMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *pgconn.PgError
+		wantStatus int
+		wantField  string
+	}{
+		{
+			name: "known check constraint",
+			err: &pgconn.PgError{Code: "23514", ConstraintName: "chk_settings_pdn", ColumnName: "pdn",
+				Message: `new row for relation "settings" violates check constraint "chk_settings_pdn"`},
+			wantStatus: http.StatusConflict,
+			wantField:  "pdn",
+		},
+		{
+			name: "known unique index",
+			err: &pgconn.PgError{Code: "23505", ConstraintName: "idx_organizations_name",
+				Message: `duplicate key value violates unique constraint "idx_organizations_name"`},
+			wantStatus: http.StatusConflict,
+			wantField:  "name",
+		},
+		{
+			name: "unknown constraint",
+			err: &pgconn.PgError{Code: "23503", ConstraintName: "fk_webhook_deliveries_event",
+				Message: `insert or update on table "webhook_deliveries" violates foreign key constraint "fk_webhook_deliveries_event"`},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not a constraint violation",
+			err:        &pgconn.PgError{Code: "42P01", Message: `relation "settings" does not exist`},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FromError(fmt.Errorf("save: %w", tt.err))
+			if p.Status != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", p.Status, tt.wantStatus)
+			}
+
+			body, err := p.MarshalJSON()
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			for _, leaked := range []string{tt.err.Message, "relation", "constraint"} {
+				if strings.Contains(string(body), leaked) {
+					t.Errorf("problem %s exposes %q", body, leaked)
+				}
+			}
+
+			switch {
+			case tt.wantField == "" && len(p.Errors) != 0:
+				t.Errorf("Errors = %+v, want none", p.Errors)
+			case tt.wantField != "" && (len(p.Errors) != 1 || p.Errors[0].Field != tt.wantField):
+				t.Errorf("Errors = %+v, want field %s", p.Errors, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APIKey struct {
+	Repository *Repository
+}
+
+func NewAPIKeyRepository(r *Repository) *APIKey {
+	return &APIKey{
+		Repository: r,
+	}
+}
+
+func (a *APIKey) Create(ctx context.Context, organizationID uuid.UUID, name, prefix, keyHash string) (*domain.APIKey, error) {
+	model := &models.APIKey{
+		OrganizationUUID: &organizationID,
+		Name:             name,
+		Prefix:           prefix,
+		KeyHash:          keyHash,
+	}
+
+	result := a.Repository.db.WithContext(ctx).Table("api_keys").Omit("Organization").Create(model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domain.APIKeyFromModel(model), nil
+}
+
+func (a *APIKey) GetByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*domain.APIKey, error) {
+	var keys []*models.APIKey
+	result := a.Repository.db.WithContext(ctx).
+		Table("api_keys").
+		Where("organization_uuid = ?", organizationID).
+		Order("created_at").
+		Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	domainKeys := make([]*domain.APIKey, len(keys))
+	for i, key := range keys {
+		domainKeys[i] = domain.APIKeyFromModel(key)
+	}
+
+	return domainKeys, nil
+}
+
+func (a *APIKey) Revoke(ctx context.Context, organizationID, id uuid.UUID) error {
+	result := a.Repository.db.WithContext(ctx).
+		Table("api_keys").
+		Where("uuid = ? AND organization_uuid = ? AND revoked_at IS NULL", id, organizationID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return internal.ErrRecordNoFound
+	}
+	return nil
+}
+
+// FindOrganizationByHash возвращает организацию по хэшу действующего ключа и отмечает его использование
+func (a *APIKey) FindOrganizationByHash(ctx context.Context, keyHash string) (*domain.Organization, error) {
+	key := &models.APIKey{}
+	result := a.Repository.db.WithContext(ctx).
+		Table("api_keys").
+		Preload("Organization").
+		Where("key_hash = ? AND revoked_at IS NULL", keyHash).
+		First(key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
+	}
+	if key.Organization.UUID == nil {
+		return nil, internal.ErrRecordNoFound
+	}
+
+	result = a.Repository.db.WithContext(ctx).
+		Table("api_keys").
+		Where("id = ?", key.ID).
+		UpdateColumn("last_used_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domain.FromModel(&key.Organization), nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Audit struct {
+	Repository *Repository
+}
+
+func NewAuditRepository(r *Repository) *Audit {
+	return &Audit{
+		Repository: r,
+	}
+}
+
+func (a *Audit) List(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	query := a.Repository.db.WithContext(ctx).Table("audit_logs")
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityUUID != nil {
+		query = query.Where("entity_uuid = ?", *filter.EntityUUID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+
+	var logs []*models.AuditLog
+	result := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&logs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	entries := make([]*domain.AuditEntry, len(logs))
+	for i, log := range logs {
+		entries[i] = domain.AuditEntryFromModel(log)
+	}
+
+	return entries, nil
+}
+
+// writeAudit пишет запись аудита в переданной транзакции.
+// before и after сериализуются в JSON, diff содержит только изменившиеся поля.
+func writeAudit(ctx context.Context, tx *gorm.DB, entityType string, entityUUID uuid.UUID, action string, before, after interface{}) error {
+	beforeJSON, beforeFields, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, afterFields, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+	diffJSON, err := auditDiff(beforeFields, afterFields)
+	if err != nil {
+		return err
+	}
+
+	log := &models.AuditLog{
+		EntityType: entityType,
+		EntityUUID: &entityUUID,
+		Actor:      auth.ActorFromContext(ctx),
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		Diff:       diffJSON,
+	}
+	return tx.Table("audit_logs").Create(log).Error
+}
+
+func auditSnapshot(value interface{}) (*string, map[string]interface{}, error) {
+	if value == nil || reflect.ValueOf(value).IsNil() {
+		return nil, nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+
+	snapshot := string(data)
+	return &snapshot, fields, nil
+}
+
+func auditDiff(before, after map[string]interface{}) (*string, error) {
+	type change struct {
+		From interface{} `json:"from"`
+		To   interface{} `json:"to"`
+	}
+
+	diff := make(map[string]change)
+	for key, value := range after {
+		if key == "updated_at" {
+			continue
+		}
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			diff[key] = change{From: before[key], To: value}
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			diff[key] = change{From: value, To: nil}
+		}
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	result := string(data)
+	return &result, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"app_aggregator/internal/circuitbreaker"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/metrics"
+	"app_aggregator/internal/models"
+	"app_aggregator/internal/tracing"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Client struct {
+	Repository *Repository
+}
+
+func NewClientRepository(r *Repository) *Client {
+	return &Client{
+		Repository: r,
+	}
+}
+
+func (c *Client) FindClientHistory(ctx context.Context, phone string) ([]*domain.ClientHistory, error) {
+	sources := c.Repository.clientSources.All()
+
+	organizationNames, err := c.organizationNames(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	// Источники опрашиваются параллельно, недоступный источник не прерывает поиск
+	histories := make([]*domain.ClientHistory, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source domain.ClientSource) {
+			defer wg.Done()
+
+			sourceCtx, cancel := context.WithTimeout(ctx, source.Timeout())
+			defer cancel()
+			sourceCtx, span := tracing.Start(sourceCtx, "client_source.lookup",
+				trace.WithAttributes(tracing.SourceAttribute.String(source.Name())))
+
+			start := time.Now()
+			history, err := clientHistory(sourceCtx, source, phone)
+			span.SetAttributes(attribute.String("client_lookup.status", string(lookupStatus(history, err))))
+			tracing.End(span, err)
+			if err != nil {
+				history = &domain.ClientHistory{Status: domain.ClientLookupUnknown}
+				metrics.ClientSourceLookupErrors.WithLabelValues(source.Name(), lookupErrorReason(err)).Inc()
+			}
+			metrics.ClientSourceLookupDuration.WithLabelValues(source.Name(), string(history.Status)).
+				Observe(time.Since(start).Seconds())
+			history.Source = source.Name()
+			history.OrganizationUUID = source.OrganizationUUID()
+			history.OrganizationName = organizationNames[source.OrganizationUUID()]
+			histories[i] = history
+		}(i, source)
+	}
+	wg.Wait()
+
+	return histories, nil
+}
+
+func (c *Client) SourcesHealth(ctx context.Context) ([]*domain.ClientSourceHealth, error) {
+	return c.Repository.clientSources.Health(), nil
+}
+
+func (c *Client) organizationNames(ctx context.Context, sources []domain.ClientSource) (map[uuid.UUID]string, error) {
+	ids := make([]uuid.UUID, 0, len(sources))
+	for _, source := range sources {
+		ids = append(ids, source.OrganizationUUID())
+	}
+
+	names := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	var organizations []*models.Organization
+	result := c.Repository.db.WithContext(ctx).Table("organizations").Where("uuid IN ?", ids).Find(&organizations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, organization := range organizations {
+		if organization.UUID != nil {
+			names[*organization.UUID] = organization.Name
+		}
+	}
+	return names, nil
+}
+
+func clientHistory(ctx context.Context, source domain.ClientSource, phone string) (*domain.ClientHistory, error) {
+	clientID, err := source.FindClient(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if clientID == "" {
+		return &domain.ClientHistory{Status: domain.ClientLookupNotFound}, nil
+	}
+
+	history, err := source.LoanHistory(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	history.Status = domain.ClientLookupFound
+	return history, nil
+}
+
+func lookupErrorReason(err error) string {
+	switch {
+	case errors.Is(err, circuitbreaker.ErrOpen):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	return "error"
+}
+
+func lookupStatus(history *domain.ClientHistory, err error) domain.ClientLookupStatus {
+	if err != nil {
+		return domain.ClientLookupUnknown
+	}
+	return history.Status
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"app_aggregator/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MSSQLClientSource - legacy база кредитора на MSSQL
+type MSSQLClientSource struct {
+	name             string
+	organizationUUID uuid.UUID
+	timeout          time.Duration
+	db               *gorm.DB
+}
+
+func NewMSSQLClientSource(name string, organizationUUID uuid.UUID, timeout time.Duration, db *gorm.DB) *MSSQLClientSource {
+	return &MSSQLClientSource{
+		name:             name,
+		organizationUUID: organizationUUID,
+		timeout:          timeout,
+		db:               db,
+	}
+}
+
+func (s *MSSQLClientSource) Name() string {
+	return s.name
+}
+
+func (s *MSSQLClientSource) OrganizationUUID() uuid.UUID {
+	return s.organizationUUID
+}
+
+func (s *MSSQLClientSource) Timeout() time.Duration {
+	return s.timeout
+}
+
+func (s *MSSQLClientSource) FindClient(ctx context.Context, phone string) (string, error) {
+	return findClient(ctx, phone, s.db)
+}
+
+func (s *MSSQLClientSource) LoanHistory(ctx context.Context, clientID string) (*domain.ClientHistory, error) {
+	var err error
+	history := &domain.ClientHistory{ClientID: clientID}
+
+	history.ClientFullName, err = clientFullName(ctx, s.db, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	history.HasLoans, err = clientHasLoans(ctx, s.db, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !history.HasLoans {
+		return history, nil
+	}
+
+	history.HasActiveLoan, err = clientActiveLoanCheck(ctx, s.db, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if history.HasActiveLoan {
+		history.ActiveLoanNumber, err = clientActiveLoanNumber(ctx, s.db, clientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	history.LastPdn, err = clientLastPdn(ctx, s.db, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func findClient(ctx context.Context, phoneNumber string, db *gorm.DB) (string, error) {
+	type clientResult struct {
+		ClientID    string `gorm:"column:ClientId"`
+		ClearNumber string `gorm:"column:ClearNumber"`
+	}
+
+	var result clientResult
+	query := `
+		SELECT pn.ClientId, pn.ClearNumber 
+		FROM PhoneNumbers pn 
+		INNER JOIN Clients c ON pn.Client_Id = c.id 
+		  AND c.PrimaryPhoneNumberId = pn.Id
+		WHERE pn.ClearNumber = ?
+	`
+
+	// В legacy базах номер хранится без +: 79123456789
+	err := db.WithContext(ctx).Raw(query, strings.TrimPrefix(phoneNumber, "+")).First(&result).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return result.ClientID, nil
+}
+
+func clientActiveLoanCheck(ctx context.Context, db *gorm.DB, id string) (bool, error) {
+
+	var activeLoansCount int64
+	result := db.WithContext(ctx).Table("Loans").Where("ClientId = ? AND IsActive = ?", id, 1).Count(&activeLoansCount)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return activeLoansCount > 0, nil
+}
+
+func clientHasLoans(ctx context.Context, db *gorm.DB, id string) (bool, error) {
+	var activeLoansCount int64
+	result := db.WithContext(ctx).Table("Loans").Where("ClientId = ?", id).Count(&activeLoansCount)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return activeLoansCount > 0, nil
+}
+
+func clientActiveLoanNumber(ctx context.Context, db *gorm.DB, id string) (string, error) {
+	var activeLoanNumber string
+	result := db.WithContext(ctx).Table("Loans").Select("Number").Where("ClientId = ? AND IsActive = ?", id, 1).Take(&activeLoanNumber)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return activeLoanNumber, nil
+}
+
+func clientLastPdn(ctx context.Context, db *gorm.DB, id string) (string, error) {
+	var lastPdn string
+	result := db.WithContext(ctx).Table("Loans").Select("Pdn").Where("ClientId = ?", id).Order("created_at DESC").Take(&lastPdn)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return lastPdn, nil
+}
+
+func clientFullName(ctx context.Context, db *gorm.DB, id string) (string, error) {
+	type clientName struct {
+		LastName   string `gorm:"column:LastName"`
+		FirstName  string `gorm:"column:FirstName"`
+		MiddleName string `gorm:"column:MiddleName"`
+	}
+
+	var name clientName
+	result := db.WithContext(ctx).Table("Clients").Select("LastName, FirstName, MiddleName").Where("Id = ?", id).Take(&name)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", result.Error
+	}
+
+	parts := make([]string, 0, 3)
+	for _, part := range []string{name.LastName, name.FirstName, name.MiddleName} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
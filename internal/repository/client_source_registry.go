@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/circuitbreaker"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/metrics"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientSourceRegistry хранит подключенные legacy источники в порядке регистрации.
+// Каждый источник оборачивается в circuit breaker.
+type ClientSourceRegistry struct {
+	sources       []*breakerClientSource
+	byName        map[string]*breakerClientSource
+	breakerConfig *circuitbreaker.Config
+	logger        *slog.Logger
+	mu            sync.RWMutex
+}
+
+func NewClientSourceRegistry(breakerConfig *circuitbreaker.Config, logger *slog.Logger) *ClientSourceRegistry {
+	return &ClientSourceRegistry{
+		byName:        make(map[string]*breakerClientSource),
+		breakerConfig: breakerConfig,
+		logger:        logger,
+	}
+}
+
+func (r *ClientSourceRegistry) Register(source domain.ClientSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byName[source.Name()]; exists {
+		return internal.ErrClientSourceExists
+	}
+
+	wrapped := &breakerClientSource{
+		ClientSource: source,
+		breaker:      circuitbreaker.NewBreaker(source.Name(), r.breakerConfig, r.logStateChange),
+	}
+	r.sources = append(r.sources, wrapped)
+	r.byName[source.Name()] = wrapped
+	metrics.ClientSourceCircuitState.WithLabelValues(source.Name()).Set(circuitStateValue(circuitbreaker.StateClosed))
+	return nil
+}
+
+func (r *ClientSourceRegistry) Get(name string) (domain.ClientSource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	source, ok := r.byName[name]
+	if !ok {
+		return nil, false
+	}
+	return source, true
+}
+
+func (r *ClientSourceRegistry) All() []domain.ClientSource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sources := make([]domain.ClientSource, len(r.sources))
+	for i, source := range r.sources {
+		sources[i] = source
+	}
+	return sources
+}
+
+func (r *ClientSourceRegistry) Health() []*domain.ClientSourceHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	health := make([]*domain.ClientSourceHealth, len(r.sources))
+	for i, source := range r.sources {
+		health[i] = clientSourceHealth(source.Name(), source.OrganizationUUID(), source.breaker.Stats())
+	}
+	return health
+}
+
+func (r *ClientSourceRegistry) logStateChange(name string, from, to circuitbreaker.State) {
+	level := slog.LevelWarn
+	if to == circuitbreaker.StateClosed {
+		level = slog.LevelInfo
+	}
+	r.logger.Log(context.Background(), level, "Client source circuit breaker state changed",
+		slog.String("source", name),
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+	)
+	metrics.ClientSourceCircuitState.WithLabelValues(name).Set(circuitStateValue(to))
+}
+
+// circuitStateValue упорядочивает состояния по степени деградации для графиков
+func circuitStateValue(state circuitbreaker.State) float64 {
+	switch state {
+	case circuitbreaker.StateHalfOpen:
+		return 1
+	case circuitbreaker.StateOpen:
+		return 2
+	}
+	return 0
+}
+
+type breakerClientSource struct {
+	domain.ClientSource
+	breaker *circuitbreaker.Breaker
+}
+
+func (s *breakerClientSource) FindClient(ctx context.Context, phone string) (string, error) {
+	var clientID string
+	err := s.execute(ctx, func() error {
+		var err error
+		clientID, err = s.ClientSource.FindClient(ctx, phone)
+		return err
+	})
+	return clientID, err
+}
+
+func (s *breakerClientSource) LoanHistory(ctx context.Context, clientID string) (*domain.ClientHistory, error) {
+	var history *domain.ClientHistory
+	err := s.execute(ctx, func() error {
+		var err error
+		history, err = s.ClientSource.LoanHistory(ctx, clientID)
+		return err
+	})
+	return history, err
+}
+
+// execute не считает отмену запроса клиентом ни отказом, ни ответом источника
+func (s *breakerClientSource) execute(ctx context.Context, fn func() error) error {
+	return s.breaker.Execute(func() error {
+		err := fn()
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			return circuitbreaker.Ignore(err)
+		}
+		return err
+	})
+}
+
+func clientSourceHealth(name string, organizationUUID uuid.UUID, stats circuitbreaker.Stats) *domain.ClientSourceHealth {
+	return &domain.ClientSourceHealth{
+		Name:                name,
+		OrganizationUUID:    organizationUUID,
+		State:               stats.State,
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+		TotalSuccesses:      stats.TotalSuccesses,
+		TotalFailures:       stats.TotalFailures,
+		Rejected:            stats.Rejected,
+		LastError:           stats.LastError,
+		LastSuccessAt:       timePtr(stats.LastSuccessAt),
+		LastFailureAt:       timePtr(stats.LastFailureAt),
+		OpenedAt:            timePtr(stats.OpenedAt),
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
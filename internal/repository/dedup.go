@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Dedup struct {
+	Repository *Repository
+}
+
+func NewDedupRepository(r *Repository) *Dedup {
+	return &Dedup{
+		Repository: r,
+	}
+}
+
+func (d *Dedup) GetPolicy(ctx context.Context, organizationID uuid.UUID) (*domain.DedupPolicy, error) {
+	policy := &models.DedupPolicy{}
+	result := d.Repository.db.WithContext(ctx).
+		Table("dedup_policies").
+		Where("organization_uuid = ?", organizationID).
+		First(policy)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
+	}
+	return domain.DedupPolicyFromModel(policy), nil
+}
+
+func (d *Dedup) SavePolicy(ctx context.Context, policy *domain.DedupPolicy) (*domain.DedupPolicy, error) {
+	organizationUUID := policy.OrganizationUUID
+	model := &models.DedupPolicy{
+		OrganizationUUID: &organizationUUID,
+		WindowHours:      policy.WindowHours,
+		Scope:            string(policy.Scope),
+		Action:           string(policy.Action),
+	}
+
+	result := d.Repository.db.WithContext(ctx).
+		Table("dedup_policies").
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_uuid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"window_hours", "scope", "action", "updated_at"}),
+		}).
+		Create(model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domain.DedupPolicyFromModel(model), nil
+}
+
+// DuplicateChain возвращает цепочку дублей, в которую входит заявка
+func (d *Dedup) DuplicateChain(ctx context.Context, loanApplicationID uuid.UUID) (*domain.DuplicateChain, error) {
+	db := d.Repository.db.WithContext(ctx)
+
+	loanApplication, err := d.Repository.loadLoanApplication(db, loanApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	originalUUID := chainOriginal(loanApplication)
+
+	var duplicates []*models.LoanApplicationDuplicate
+	result := db.Table("loan_application_duplicates").
+		Preload("IncomingOrganization").
+		Where("original_uuid = ?", originalUUID).
+		Order("created_at, id").
+		Find(&duplicates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	chain := &domain.DuplicateChain{
+		OriginalUUID: originalUUID,
+		Duplicates:   make([]*domain.LoanApplicationDuplicate, len(duplicates)),
+	}
+	for i, duplicate := range duplicates {
+		chain.Duplicates[i] = domain.LoanApplicationDuplicateFromModel(duplicate)
+	}
+
+	return chain, nil
+}
+
+// loadDedupPolicy возвращает политику организации или политику по умолчанию
+func loadDedupPolicy(db *gorm.DB, organizationID uuid.UUID) (*domain.DedupPolicy, error) {
+	policy := &models.DedupPolicy{}
+	result := db.Table("dedup_policies").Where("organization_uuid = ?", organizationID).Limit(1).Find(policy)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.DefaultDedupPolicy(organizationID), nil
+	}
+	return domain.DedupPolicyFromModel(policy), nil
+}
+
+// findDuplicate ищет последнюю заявку с тем же телефоном в окне политики.
+// Вызывается в транзакции после lockPhone, чтобы параллельные заявки не прошли проверку одновременно.
+func (r *Repository) findDuplicate(tx *gorm.DB, phone string, incomingOrganizationID uuid.UUID, policy *domain.DedupPolicy) (*models.LoanApplication, error) {
+	query := r.wherePhone(tx.Table("loan_applications"), phone).
+		Where("created_at >= ?", time.Now().Add(-policy.Window()))
+	if policy.Scope == domain.DedupScopeIncomingOrganization {
+		query = query.Where("incoming_organization_uuid = ?", incomingOrganizationID)
+	}
+
+	var duplicates []*models.LoanApplication
+	result := query.
+		Preload("IncomingOrganization").
+		Preload("IssueOrganization").
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Find(&duplicates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(duplicates) == 0 {
+		return nil, nil
+	}
+	if err := r.openPhone(duplicates[0]); err != nil {
+		return nil, err
+	}
+	return duplicates[0], nil
+}
+
+// checkDuplicate ищет дубль по политике входящей организации и возвращает действие, которое к нему применяется.
+// Попытка подать дубль, который отклоняется или объединяется, сразу записывается в цепочку.
+// Объединение с заявкой другой организации (scope global) отдало бы клиенту ее данные, поэтому такой дубль отклоняется.
+func (r *Repository) checkDuplicate(tx *gorm.DB, phone string, incomingOrganizationID uuid.UUID) (*models.LoanApplication, domain.DedupAction, error) {
+	policy, err := loadDedupPolicy(tx, incomingOrganizationID)
+	if err != nil {
+		return nil, "", err
+	}
+	duplicate, err := r.findDuplicate(tx, phone, incomingOrganizationID, policy)
+	if err != nil || duplicate == nil {
+		return nil, "", err
+	}
+
+	action := policy.Action
+	if action == domain.DedupActionMerge &&
+		(duplicate.IncomingOrganizationUuid == nil || *duplicate.IncomingOrganizationUuid != incomingOrganizationID) {
+		action = domain.DedupActionReject
+	}
+	if action != domain.DedupActionLink {
+		if err := recordDuplicate(tx, chainOriginal(duplicate), nil, incomingOrganizationID, action); err != nil {
+			return nil, "", err
+		}
+	}
+	return duplicate, action, nil
+}
+
+// lockPhone сериализует создание заявок с одним телефоном до конца транзакции, phoneHash - слепой индекс телефона
+func lockPhone(tx *gorm.DB, phoneHash string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", phoneHash).Error
+}
+
+// chainOriginal - исходная заявка цепочки, к которой привязываются все дубли
+func chainOriginal(loanApplication *models.LoanApplication) uuid.UUID {
+	if loanApplication.DuplicateOfUUID != nil {
+		return *loanApplication.DuplicateOfUUID
+	}
+	return *loanApplication.UUID
+}
+
+func recordDuplicate(tx *gorm.DB, originalID uuid.UUID, duplicateID *uuid.UUID, incomingOrganizationID uuid.UUID, action domain.DedupAction) error {
+	return tx.Table("loan_application_duplicates").Omit(clause.Associations).Create(&models.LoanApplicationDuplicate{
+		OriginalUUID:             &originalID,
+		DuplicateUUID:            duplicateID,
+		IncomingOrganizationUUID: &incomingOrganizationID,
+		Action:                   string(action),
+	}).Error
+}
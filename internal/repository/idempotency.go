@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Idempotency struct {
+	Repository *Repository
+}
+
+func NewIdempotencyRepository(r *Repository) *Idempotency {
+	return &Idempotency{
+		Repository: r,
+	}
+}
+
+// Reserve занимает ключ под выполняющийся запрос. Если ключ уже занят,
+// возвращает существующую запись и false. Просроченная запись при этом заменяется.
+func (i *Idempotency) Reserve(ctx context.Context, organizationID uuid.UUID, key, requestHash string, expiresAt time.Time) (*domain.IdempotencyRecord, bool, error) {
+	var existing *models.IdempotencyKey
+	reserved := false
+	err := i.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("idempotency_keys").
+			Where("organization_uuid = ? AND key = ? AND expires_at < now()", organizationID, key).
+			Delete(&models.IdempotencyKey{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		model := &models.IdempotencyKey{
+			OrganizationUUID: &organizationID,
+			Key:              key,
+			RequestHash:      requestHash,
+			ExpiresAt:        expiresAt,
+		}
+		result = tx.Table("idempotency_keys").Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			reserved = true
+			return nil
+		}
+
+		existing = &models.IdempotencyKey{}
+		return tx.Table("idempotency_keys").
+			Where("organization_uuid = ? AND key = ?", organizationID, key).
+			First(existing).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, internal.ErrRecordNoFound
+		}
+		return nil, false, err
+	}
+
+	return domain.IdempotencyRecordFromModel(existing), reserved, nil
+}
+
+func (i *Idempotency) Complete(ctx context.Context, organizationID uuid.UUID, key string, statusCode int, body []byte) error {
+	return i.Repository.db.WithContext(ctx).
+		Table("idempotency_keys").
+		Where("organization_uuid = ? AND key = ?", organizationID, key).
+		Updates(map[string]interface{}{
+			"status_code":   statusCode,
+			"response_body": body,
+		}).Error
+}
+
+// Release освобождает ключ, если ответ на запрос так и не был сохранен
+func (i *Idempotency) Release(ctx context.Context, organizationID uuid.UUID, key string) error {
+	return i.Repository.db.WithContext(ctx).
+		Table("idempotency_keys").
+		Where("organization_uuid = ? AND key = ? AND status_code = 0", organizationID, key).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+func (i *Idempotency) DeleteExpired(ctx context.Context) (int64, error) {
+	result := i.Repository.db.WithContext(ctx).
+		Table("idempotency_keys").
+		Where("expires_at < now()").
+		Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Import struct {
+	Repository *Repository
+}
+
+func NewImportRepository(r *Repository) *Import {
+	return &Import{
+		Repository: r,
+	}
+}
+
+func (i *Import) CreateJob(ctx context.Context, job *domain.ImportJob) (*domain.ImportJob, error) {
+	model := &models.ImportJob{
+		OrganizationUUID: &job.OrganizationUUID,
+		FileName:         job.FileName,
+		Status:           string(job.Status),
+		TotalRows:        job.TotalRows,
+		Actor:            job.Actor,
+	}
+
+	result := i.Repository.db.WithContext(ctx).Table("import_jobs").Omit("Organization").Create(model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domain.ImportJobFromModel(model), nil
+}
+
+func (i *Import) GetJob(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	model, err := loadImportJob(i.Repository.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ImportJobFromModel(model), nil
+}
+
+// SaveProgress сохраняет результаты очередной пачки строк вместе со счетчиками и статусом задачи
+func (i *Import) SaveProgress(ctx context.Context, job *domain.ImportJob, results []*domain.ImportRowResult) error {
+	return i.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model, err := loadImportJob(tx, job.UUID)
+		if err != nil {
+			return err
+		}
+
+		if len(results) > 0 {
+			rows := make([]*models.ImportJobRow, len(results))
+			for n, result := range results {
+				rows[n] = &models.ImportJobRow{
+					JobID:               model.ID,
+					Line:                result.Line,
+					Status:              string(result.Status),
+					Reason:              result.Reason,
+					LoanApplicationUUID: result.LoanApplicationUUID,
+				}
+			}
+			if err := tx.Table("import_job_rows").Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Table("import_jobs").
+			Where("id = ?", model.ID).
+			Updates(map[string]interface{}{
+				"status":         string(job.Status),
+				"processed_rows": job.ProcessedRows,
+				"created":        job.Created,
+				"duplicates":     job.Duplicates,
+				"invalid":        job.Invalid,
+				"failed":         job.Failed,
+				"error":          job.Error,
+				"finished_at":    job.FinishedAt,
+				"updated_at":     time.Now(),
+			}).Error
+	})
+}
+
+func (i *Import) ListRows(ctx context.Context, jobID uuid.UUID, filter *domain.ImportRowFilter) ([]*domain.ImportRowResult, error) {
+	job, err := loadImportJob(i.Repository.db.WithContext(ctx), jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := i.Repository.db.WithContext(ctx).Table("import_job_rows").Where("job_id = ?", job.ID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+
+	var rows []*models.ImportJobRow
+	result := query.Order("line").Limit(filter.Limit).Offset(filter.Offset).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	results := make([]*domain.ImportRowResult, len(rows))
+	for n, row := range rows {
+		results[n] = domain.ImportRowResultFromModel(row)
+	}
+
+	return results, nil
+}
+
+// FailStale завершает с ошибкой задачи, которые не обновлялись с before: их обработчик остановился вместе с процессом
+func (i *Import) FailStale(ctx context.Context, before time.Time) (int64, error) {
+	now := time.Now()
+	result := i.Repository.db.WithContext(ctx).
+		Table("import_jobs").
+		Where("status = ? AND updated_at < ?", string(domain.ImportJobStatusRunning), before).
+		Updates(map[string]interface{}{
+			"status":      string(domain.ImportJobStatusFailed),
+			"error":       "import was interrupted",
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func loadImportJob(db *gorm.DB, id uuid.UUID) (*models.ImportJob, error) {
+	job := &models.ImportJob{}
+	result := db.Table("import_jobs").Where("uuid = ?", id).First(job)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
+	}
+	return job, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// loanApplicationCursor - позиция keyset пагинации: значение поля сортировки и id последней строки
+type loanApplicationCursor struct {
+	Sort      string     `json:"s"`
+	CreatedAt *time.Time `json:"c,omitempty"`
+	Value     *int64     `json:"v,omitempty"`
+	ID        uint       `json:"id"`
+}
+
+// applyLoanApplicationFilter добавляет к запросу условия фильтра, сортировку и позицию курсора
+func applyLoanApplicationFilter(query *gorm.DB, filter *domain.LoanApplicationFilter) (*gorm.DB, error) {
+	if filter.IncomingOrganizationUUID != nil {
+		query = query.Where("incoming_organization_uuid = ?", *filter.IncomingOrganizationUUID)
+	}
+	if filter.IssueOrganizationUUID != nil {
+		query = query.Where("issue_organization_uuid = ?", *filter.IssueOrganizationUUID)
+	}
+	if filter.MinValue != nil {
+		query = query.Where("value >= ?", *filter.MinValue)
+	}
+	if filter.MaxValue != nil {
+		query = query.Where("value <= ?", *filter.MaxValue)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+
+	comparison := ">"
+	if filter.Order == domain.SortOrderDesc {
+		comparison = "<"
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeLoanApplicationCursor(filter.Cursor)
+		if err != nil || cursor.Sort != filter.Sort {
+			return nil, internal.ErrInvalidFilter
+		}
+
+		switch {
+		case filter.Sort == domain.LoanApplicationSortValue && cursor.Value != nil:
+			query = query.Where(fmt.Sprintf("(value, id) %s (?, ?)", comparison), *cursor.Value, cursor.ID)
+		case filter.Sort == domain.LoanApplicationSortCreatedAt && cursor.CreatedAt != nil:
+			query = query.Where(fmt.Sprintf("(created_at, id) %s (?, ?)", comparison), *cursor.CreatedAt, cursor.ID)
+		default:
+			return nil, internal.ErrInvalidFilter
+		}
+	}
+
+	direction := strings.ToUpper(filter.Order)
+	return query.Order(fmt.Sprintf("%s %s, id %s", filter.Sort, direction, direction)), nil
+}
+
+func encodeLoanApplicationCursor(sort string, last *models.LoanApplication) string {
+	cursor := loanApplicationCursor{
+		Sort: sort,
+		ID:   last.ID,
+	}
+	switch sort {
+	case domain.LoanApplicationSortValue:
+		cursor.Value = &last.Value
+	default:
+		cursor.CreatedAt = &last.CreatedAt
+	}
+
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeLoanApplicationCursor(value string) (*loanApplicationCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &loanApplicationCursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
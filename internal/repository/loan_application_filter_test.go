@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"testing"
+	"time"
+)
+
+func TestLoanApplicationCursor(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 10, 30, 0, 123000, time.UTC)
+	last := &models.LoanApplication{Value: 150000}
+	last.ID = 42
+	last.CreatedAt = createdAt
+
+	tests := []struct {
+		name string
+		sort string
+	}{
+		{"created_at", domain.LoanApplicationSortCreatedAt},
+		{"value", domain.LoanApplicationSortValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeLoanApplicationCursor(encodeLoanApplicationCursor(tt.sort, last))
+			if err != nil {
+				t.Fatalf("decodeLoanApplicationCursor() error = %v", err)
+			}
+			if cursor.Sort != tt.sort || cursor.ID != 42 {
+				t.Errorf("cursor = %+v, want sort %s and id 42", cursor, tt.sort)
+			}
+
+			switch tt.sort {
+			case domain.LoanApplicationSortValue:
+				if cursor.Value == nil || *cursor.Value != 150000 || cursor.CreatedAt != nil {
+					t.Errorf("cursor = %+v, want value 150000 only", cursor)
+				}
+			default:
+				if cursor.CreatedAt == nil || !cursor.CreatedAt.Equal(createdAt) || cursor.Value != nil {
+					t.Errorf("cursor = %+v, want created_at %s only", cursor, createdAt)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeLoanApplicationCursorInvalid(t *testing.T) {
+	for _, value := range []string{"%%%", "bm90IGpzb24"} {
+		if _, err := decodeLoanApplicationCursor(value); err == nil {
+			t.Errorf("decodeLoanApplicationCursor(%q) error = nil", value)
+		}
+	}
+}
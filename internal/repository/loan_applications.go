@@ -7,9 +7,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LoanApplicationsRepository struct {
@@ -22,256 +24,416 @@ func NewLoanApplicationsRepository(repository *Repository) *LoanApplicationsRepo
 	}
 }
 
-func (r *LoanApplicationsRepository) GetAll(ctx context.Context) ([]*domain.LoanApplication, error) {
+func (r *LoanApplicationsRepository) GetAll(ctx context.Context, filter *domain.LoanApplicationFilter) (*domain.LoanApplicationPage, error) {
+	query, err := applyLoanApplicationFilter(r.Repository.db.WithContext(ctx).Table("loan_applications"), filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Phone != "" {
+		query = r.Repository.wherePhone(query, filter.Phone)
+	}
+
 	var loanApplications []*models.LoanApplication
-	result := r.Repository.db.WithContext(ctx).
-		Table("loan_applications").
+	result := query.
 		Preload("IncomingOrganization").
 		Preload("IssueOrganization").
+		Limit(filter.Limit + 1).
 		Find(&loanApplications)
 	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, internal.ErrRecordNoFound
-		}
 		return nil, result.Error
 	}
 
-	domainApplications := make([]*domain.LoanApplication, len(loanApplications))
+	page := &domain.LoanApplicationPage{}
+	if len(loanApplications) > filter.Limit {
+		loanApplications = loanApplications[:filter.Limit]
+		page.NextCursor = encodeLoanApplicationCursor(filter.Sort, loanApplications[len(loanApplications)-1])
+	}
+
+	page.Items = make([]*domain.LoanApplication, len(loanApplications))
 	for i, app := range loanApplications {
-		domainApplications[i] = domain.LoanApplicationFromModel(app)
+		if err := r.Repository.openPhone(app); err != nil {
+			return nil, err
+		}
+		page.Items[i] = domain.LoanApplicationFromModel(app)
 	}
 
-	return domainApplications, nil
+	return page, nil
 }
 
 func (r *LoanApplicationsRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.LoanApplication, error) {
-	loanApplication := &models.LoanApplication{}
-	result := r.Repository.db.WithContext(ctx).
-		Table("loan_applications").
-		Preload("IncomingOrganization").
-		Preload("IssueOrganization").
-		Where("uuid = ?", id).
-		First(loanApplication)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, internal.ErrRecordNoFound
-		}
-		return nil, result.Error
+	loanApplication, err := r.Repository.loadLoanApplication(r.Repository.db.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
 	}
 	return domain.LoanApplicationFromModel(loanApplication), nil
 }
 
-func (r *LoanApplicationsRepository) Create(ctx context.Context, loanApplication *domain.LoanApplication) (*domain.LoanApplication, error) {
+// Deduplicate проверяет заявку по политике дублей до маршрутизации и запросов к legacy источникам.
+// Возвращает существующую заявку с Merged, если политика объединяет дубли, и ошибку, если отклоняет.
+// nil без ошибки - заявку нужно создавать, Create повторяет проверку под блокировкой телефона.
+func (r *LoanApplicationsRepository) Deduplicate(ctx context.Context, loanApplication *domain.LoanApplication) (*domain.LoanApplication, error) {
 	incomingOrg, err := r.FindOrganizationByName(ctx, loanApplication.IncomingOrganizationName)
 	if err != nil {
 		return nil, err
 	}
-	doverixClientId, err := findClient(ctx, loanApplication.Phone, r.Repository.doverixDb)
+
+	var merged *models.LoanApplication
+	var rejected error
+	err = r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPhone(tx, r.Repository.phones.Index(loanApplication.Phone)); err != nil {
+			return err
+		}
+
+		duplicate, action, err := r.Repository.checkDuplicate(tx, loanApplication.Phone, *incomingOrg.UUID)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case domain.DedupActionReject:
+			// попытка сохраняется, поэтому транзакция фиксируется, а ошибка возвращается после нее
+			rejected = &internal.DuplicateLoanApplicationError{OriginalUUID: chainOriginal(duplicate)}
+		case domain.DedupActionMerge:
+			merged = duplicate
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	kassaClientId, err := findClient(ctx, loanApplication.Phone, r.Repository.kassaDb)
+	if rejected != nil {
+		return nil, rejected
+	}
+	if merged == nil {
+		return nil, nil
+	}
+
+	app := domain.LoanApplicationFromModel(merged)
+	app.Merged = true
+	return app, nil
+}
+
+// Create сохраняет заявку, выданную организации из решения маршрутизации
+func (r *LoanApplicationsRepository) Create(ctx context.Context, loanApplication *domain.LoanApplication, decision *domain.RoutingDecision) (*domain.LoanApplication, error) {
+	incomingOrg, err := r.FindOrganizationByName(ctx, loanApplication.IncomingOrganizationName)
 	if err != nil {
 		return nil, err
 	}
-	denedClientId, err := findClient(ctx, loanApplication.Phone, r.Repository.deDb)
-	if err != nil {
+
+	model := loanApplication.ToModel()
+	model.IncomingOrganizationUuid = incomingOrg.UUID
+	model.IssueOrganizationUuid = decision.Organization.UUID
+	if err := r.Repository.sealPhone(model); err != nil {
 		return nil, err
 	}
 
-	var issueOrg *domain.Organization
+	var created *models.LoanApplication
+	var rejected error
+	merged := false
+	err = r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPhone(tx, model.PhoneHash); err != nil {
+			return err
+		}
 
-	if doverixClientId == "" && kassaClientId == "" && denedClientId == "" {
-		issueOrg, err = r.FindOrganizationByName(ctx, "dened.ru")
+		duplicate, action, err := r.Repository.checkDuplicate(tx, loanApplication.Phone, *incomingOrg.UUID)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		switch action {
+		case domain.DedupActionReject:
+			rejected = &internal.DuplicateLoanApplicationError{OriginalUUID: chainOriginal(duplicate)}
+			return nil
+		case domain.DedupActionMerge:
+			merged = true
+			created = duplicate
+			return nil
+		case domain.DedupActionLink:
+			originalID := chainOriginal(duplicate)
+			model.DuplicateOfUUID = &originalID
 		}
-	}
 
-	fmt.Println("doverixClientId: ", doverixClientId)
-	fmt.Println("kassaClientId: ", kassaClientId)
-	fmt.Println("denedClientId: ", denedClientId)
+		result := tx.Table("loan_applications").Create(model)
+		if result.Error != nil {
+			return result.Error
+		}
 
-	model := loanApplication.ToModel()
-	model.IncomingOrganizationUuid = incomingOrg.UUID
-	model.IssueOrganizationUuid = issueOrg.UUID
+		created, err = r.Repository.loadLoanApplication(tx, *model.UUID)
+		if err != nil {
+			return err
+		}
+
+		if model.DuplicateOfUUID != nil {
+			err := recordDuplicate(tx, *model.DuplicateOfUUID, model.UUID, *incomingOrg.UUID, domain.DedupActionLink)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = writeAudit(ctx, tx, domain.AuditEntityLoanApplication, *created.UUID, domain.AuditActionCreate,
+			nil, domain.LoanApplicationFromModel(created).Masked())
+		if err != nil {
+			return err
+		}
 
-	var count int64
-	err = r.Repository.db.WithContext(ctx).Table("loan_applications").Where("phone = ? AND created_at::date = CURRENT_DATE", model.Phone).Count(&count).Error
+		return r.Repository.writeOutboxEvent(tx, domain.EventLoanApplicationCreated, created)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if count > 0 {
-		return nil, internal.ErrPhoneNumberExistToday
-	}
-
-	result := r.Repository.db.WithContext(ctx).Table("loan_applications").Create(model)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	result = r.Repository.db.WithContext(ctx).
-		Table("loan_applications").
-		Preload("IncomingOrganization").
-		Preload("IssueOrganization").
-		Where("uuid = ?", model.UUID).
-		First(model)
-	if result.Error != nil {
-		return nil, result.Error
+	if rejected != nil {
+		return nil, rejected
 	}
 
-	return domain.LoanApplicationFromModel(model), nil
+	app := domain.LoanApplicationFromModel(created)
+	app.Merged = merged
+	return app, nil
 }
 
 func (r *LoanApplicationsRepository) Update(ctx context.Context, loanApplication *domain.LoanApplication) (*domain.LoanApplication, error) {
-	model := loanApplication.ToModel()
-
-	existingApplication := &models.LoanApplication{}
-	result := r.Repository.db.WithContext(ctx).
-		Table("loan_applications").
-		Preload("IncomingOrganization").
-		Preload("IssueOrganization").
-		Where("uuid = ?", model.UUID).
-		First(existingApplication)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, internal.ErrRecordNoFound
-		}
-		return nil, result.Error
-	}
-
+	var incomingOrg *domain.Organization
+	var err error
 	if loanApplication.IncomingOrganizationName != "" {
-		incomingOrg, err := r.FindOrganizationByName(ctx, loanApplication.IncomingOrganizationName)
+		incomingOrg, err = r.FindOrganizationByName(ctx, loanApplication.IncomingOrganizationName)
 		if err != nil {
 			return nil, err
 		}
-		existingApplication.IncomingOrganizationUuid = incomingOrg.UUID
 	}
-	if loanApplication.IssueOrganizationName != "" {
-		issueOrg, err := r.FindOrganizationByName(ctx, loanApplication.IssueOrganizationName)
+
+	var updated *models.LoanApplication
+	err = r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existingApplication, err := r.Repository.loadLoanApplication(tx, loanApplication.UUID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		existingApplication.IssueOrganizationUuid = issueOrg.UUID
-	}
-	if loanApplication.Value != 0 {
-		existingApplication.Value = loanApplication.Value
-	}
-	if loanApplication.Phone != "" {
-		existingApplication.Phone = loanApplication.Phone
-	}
-	if loanApplication.Comment != "" {
-		existingApplication.Comment = loanApplication.Comment
-	}
+		before := domain.LoanApplicationFromModel(existingApplication).Masked()
 
-	result = r.Repository.db.WithContext(ctx).Table("loan_applications").Save(existingApplication)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+		if incomingOrg != nil {
+			existingApplication.IncomingOrganizationUuid = incomingOrg.UUID
+		}
+		if loanApplication.Value != 0 {
+			existingApplication.Value = loanApplication.Value
+		}
+		if loanApplication.Phone != "" {
+			existingApplication.Phone = loanApplication.Phone
+			existingApplication.PhoneCountry = loanApplication.PhoneCountry
+			existingApplication.PhoneType = loanApplication.PhoneType
+		}
+		if loanApplication.Comment != "" {
+			existingApplication.Comment = loanApplication.Comment
+		}
+		if err := r.Repository.sealPhone(existingApplication); err != nil {
+			return err
+		}
 
-	result = r.Repository.db.WithContext(ctx).
-		Table("loan_applications").
-		Preload("IncomingOrganization").
-		Preload("IssueOrganization").
-		Where("uuid = ?", existingApplication.UUID).
-		First(existingApplication)
-	if result.Error != nil {
-		return nil, result.Error
+		result := tx.Table("loan_applications").Omit(clause.Associations).Save(existingApplication)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		updated, err = r.Repository.loadLoanApplication(tx, loanApplication.UUID)
+		if err != nil {
+			return err
+		}
+
+		err = writeAudit(ctx, tx, domain.AuditEntityLoanApplication, loanApplication.UUID, domain.AuditActionUpdate,
+			before, domain.LoanApplicationFromModel(updated).Masked())
+		if err != nil {
+			return err
+		}
+
+		return r.Repository.writeOutboxEvent(tx, domain.EventLoanApplicationUpdated, updated)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return domain.LoanApplicationFromModel(existingApplication), nil
+	return domain.LoanApplicationFromModel(updated), nil
 }
 
-func (r *LoanApplicationsRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	loan_application := &models.LoanApplication{}
+// UpdateStatus переводит заявку из статуса from в to. Если статус уже изменил параллельный запрос,
+// возвращает ErrIllegalTransition.
+func (r *LoanApplicationsRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.LoanApplicationStatus) (*domain.LoanApplication, error) {
+	var updated *models.LoanApplication
+	err := r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existingApplication, err := r.Repository.loadLoanApplication(tx, id)
+		if err != nil {
+			return err
+		}
+		before := domain.LoanApplicationFromModel(existingApplication).Masked()
 
-	result := r.Repository.db.WithContext(ctx).Table("loan_applications").Where("uuid = ?", id).First(loan_application)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return internal.ErrRecordNoFound
+		result := tx.Model(&models.LoanApplication{}).
+			Where("uuid = ? AND status = ?", id, string(from)).
+			Update("status", string(to))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return internal.ErrIllegalTransition
 		}
-		return result.Error
-	}
 
-	result = r.Repository.db.WithContext(ctx).Table("loan_applications").Delete(loan_application)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
-}
+		updated, err = r.Repository.loadLoanApplication(tx, id)
+		if err != nil {
+			return err
+		}
 
-func (r *LoanApplicationsRepository) FindOrganizationByName(ctx context.Context, name string) (*domain.Organization, error) {
-	organization := &models.Organization{}
-	result := r.Repository.db.WithContext(ctx).Table("organizations").Where("name = ?", name).First(organization)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, internal.ErrRecordNoFound
+		err = writeAudit(ctx, tx, domain.AuditEntityLoanApplication, id, domain.AuditActionTransition,
+			before, domain.LoanApplicationFromModel(updated).Masked())
+		if err != nil {
+			return err
 		}
-		return nil, result.Error
+
+		return r.Repository.writeOutboxEvent(tx, domain.EventLoanApplicationStatusChanged, updated)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return domain.FromModel(organization), nil
+
+	return domain.LoanApplicationFromModel(updated), nil
 }
 
-func findClient(ctx context.Context, phoneNumber string, db *gorm.DB) (string, error) {
-	type clientResult struct {
-		ClientID    string `gorm:"column:ClientId"`
-		ClearNumber string `gorm:"column:ClearNumber"`
-	}
+// UpdateRouting сохраняет новое решение маршрутизации и возвращает заявку в статус routed.
+// Если статус заявки уже не from, ее взял в работу параллельный запрос, и возвращается ErrRerouteNotAllowed.
+func (r *LoanApplicationsRepository) UpdateRouting(ctx context.Context, id uuid.UUID, from domain.LoanApplicationStatus, decision *domain.RoutingDecision, unknownSources []string) (*domain.LoanApplication, error) {
+	var updated *models.LoanApplication
+	err := r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existingApplication, err := r.Repository.loadLoanApplication(tx, id)
+		if err != nil {
+			return err
+		}
+		before := domain.LoanApplicationFromModel(existingApplication).Masked()
+
+		result := tx.Model(&models.LoanApplication{}).
+			Where("uuid = ? AND status = ?", id, string(from)).
+			Updates(map[string]interface{}{
+				"issue_organization_uuid": decision.Organization.UUID,
+				"status":                  string(domain.LoanApplicationStatusRouted),
+				"routing_reason":          decision.Reason,
+				"unknown_sources":         strings.Join(unknownSources, ","),
+				"settings_version":        decision.SettingsVersion,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return internal.ErrRerouteNotAllowed
+		}
 
-	var result clientResult
-	query := `
-		SELECT pn.ClientId, pn.ClearNumber 
-		FROM PhoneNumbers pn 
-		INNER JOIN Clients c ON pn.Client_Id = c.id 
-		  AND c.PrimaryPhoneNumberId = pn.Id
-		WHERE pn.ClearNumber = ?
-	`
+		updated, err = r.Repository.loadLoanApplication(tx, id)
+		if err != nil {
+			return err
+		}
 
-	err := db.WithContext(ctx).Raw(query, phoneNumber).First(&result).Error
+		err = writeAudit(ctx, tx, domain.AuditEntityLoanApplication, id, domain.AuditActionReroute,
+			before, domain.LoanApplicationFromModel(updated).Masked())
+		if err != nil {
+			return err
+		}
 
+		return r.Repository.writeOutboxEvent(tx, domain.EventLoanApplicationRouted, updated)
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil
-		}
-		return "", err
+		return nil, err
 	}
 
-	return result.ClientID, nil
+	return domain.LoanApplicationFromModel(updated), nil
 }
 
-func clientActiveLoanCheck(ctx context.Context, db *gorm.DB, id string) (bool, error) {
+func (r *LoanApplicationsRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		loanApplication, err := r.Repository.loadLoanApplication(tx, id)
+		if err != nil {
+			return err
+		}
 
-	var activeLoansCount int64
-	result := db.WithContext(ctx).Table("Loans").Where("ClientId = ? AND IsActive = ?", id, 1).Count(&activeLoansCount)
-	if result.Error != nil {
-		return false, result.Error
-	}
-	return activeLoansCount > 0, nil
+		result := tx.Table("loan_applications").Delete(loanApplication)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityLoanApplication, id, domain.AuditActionDelete,
+			domain.LoanApplicationFromModel(loanApplication).Masked(), nil)
+	})
 }
 
-func clientHasLoans(ctx context.Context, db *gorm.DB, id string) (bool, error) {
-	var activeLoansCount int64
-	result := db.WithContext(ctx).Table("Loans").Where("ClientId = ?", id).Count(&activeLoansCount)
-	if result.Error != nil {
-		return false, result.Error
+// EncryptPhones шифрует текущим ключом телефоны заявок, сохраненные в открытом виде или старым ключом,
+// пачками по batchSize. Возвращает число перешифрованных заявок.
+func (r *LoanApplicationsRepository) EncryptPhones(ctx context.Context, batchSize int) (int, error) {
+	encrypted := 0
+	var lastID uint
+	for {
+		var batch []*models.LoanApplication
+		err := r.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result := tx.Table("loan_applications").
+				Unscoped().
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("id > ?", lastID).
+				Where("(phone_encrypted IS NULL OR NOT starts_with(phone_encrypted, ?))", r.Repository.phones.CurrentKeyPrefix()).
+				Order("id").
+				Limit(batchSize).
+				Find(&batch)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			for _, model := range batch {
+				if err := r.Repository.openPhone(model); err != nil {
+					return fmt.Errorf("loan application %d: %w", model.ID, err)
+				}
+				if err := r.Repository.sealPhone(model); err != nil {
+					return err
+				}
+				result := tx.Table("loan_applications").
+					Where("id = ?", model.ID).
+					UpdateColumns(map[string]interface{}{
+						"phone":           model.Phone,
+						"phone_encrypted": model.PhoneEncrypted,
+						"phone_hash":      model.PhoneHash,
+					})
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return encrypted, err
+		}
+		if len(batch) == 0 {
+			return encrypted, nil
+		}
+
+		encrypted += len(batch)
+		lastID = batch[len(batch)-1].ID
 	}
-	return activeLoansCount > 0, nil
 }
 
-func clientActiveLoanNumber(ctx context.Context, db *gorm.DB, id string) (string, error) {
-	var activeLoanNumber string
-	result := db.WithContext(ctx).Table("Loans").Select("Number").Where("ClientId = ? AND IsActive = ?", id, 1).First(&activeLoanNumber)
+func (r *LoanApplicationsRepository) FindOrganizationByName(ctx context.Context, name string) (*domain.Organization, error) {
+	organization := &models.Organization{}
+	result := r.Repository.db.WithContext(ctx).Table("organizations").Where("name = ?", name).First(organization)
 	if result.Error != nil {
-		return "", result.Error
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
 	}
-	return activeLoanNumber, nil
+	return domain.FromModel(organization), nil
 }
 
-func clientLastPdn(ctx context.Context, db *gorm.DB, id string) (string, error) {
-	var lastPdn string
-	result := db.WithContext(ctx).Table("Loans").Select("Pdn").Where("ClientId = ?", id).Order("created_at DESC").First(&lastPdn)
+// loadLoanApplication загружает заявку с организациями и расшифрованным телефоном
+func (r *Repository) loadLoanApplication(db *gorm.DB, id uuid.UUID) (*models.LoanApplication, error) {
+	loanApplication := &models.LoanApplication{}
+	result := db.Table("loan_applications").
+		Preload("IncomingOrganization").
+		Preload("IssueOrganization").
+		Where("uuid = ?", id).
+		First(loanApplication)
 	if result.Error != nil {
-		return "", result.Error
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
+	}
+	if err := r.openPhone(loanApplication); err != nil {
+		return nil, err
 	}
-	return lastPdn, nil
+	return loanApplication, nil
 }
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Operator struct {
+	Repository *Repository
+}
+
+func NewOperatorRepository(r *Repository) *Operator {
+	return &Operator{
+		Repository: r,
+	}
+}
+
+func (o *Operator) GetAll(ctx context.Context) ([]*domain.Operator, error) {
+	var operators []*models.Operator
+	result := o.Repository.db.WithContext(ctx).Table("operators").Order("login").Find(&operators)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	domainOperators := make([]*domain.Operator, len(operators))
+	for i, operator := range operators {
+		domainOperators[i] = domain.OperatorFromModel(operator)
+	}
+
+	return domainOperators, nil
+}
+
+func (o *Operator) Create(ctx context.Context, login, passwordHash string, role domain.OperatorRole) (*domain.Operator, error) {
+	model := &models.Operator{
+		Login:        login,
+		PasswordHash: passwordHash,
+		Role:         string(role),
+	}
+
+	result := o.Repository.db.WithContext(ctx).Table("operators").Create(model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domain.OperatorFromModel(model), nil
+}
+
+// FindCredentials возвращает активного оператора и хэш его пароля
+func (o *Operator) FindCredentials(ctx context.Context, login string) (*domain.Operator, string, error) {
+	operator := &models.Operator{}
+	result := o.Repository.db.WithContext(ctx).
+		Table("operators").
+		Where("login = ? AND disabled = ?", login, false).
+		First(operator)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, "", internal.ErrRecordNoFound
+		}
+		return nil, "", result.Error
+	}
+	return domain.OperatorFromModel(operator), operator.PasswordHash, nil
+}
+
+func (o *Operator) CreateToken(ctx context.Context, operatorID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	token := &models.OperatorToken{
+		OperatorUUID: &operatorID,
+		TokenHash:    tokenHash,
+		ExpiresAt:    expiresAt,
+	}
+	return o.Repository.db.WithContext(ctx).Table("operator_tokens").Omit("Operator").Create(token).Error
+}
+
+func (o *Operator) FindByTokenHash(ctx context.Context, tokenHash string) (*domain.Operator, error) {
+	token := &models.OperatorToken{}
+	result := o.Repository.db.WithContext(ctx).
+		Table("operator_tokens").
+		Preload("Operator").
+		Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
+	}
+	if token.Operator.UUID == nil || token.Operator.Disabled {
+		return nil, internal.ErrRecordNoFound
+	}
+	return domain.OperatorFromModel(&token.Operator), nil
+}
+
+func (o *Operator) RevokeToken(ctx context.Context, tokenHash string) error {
+	result := o.Repository.db.WithContext(ctx).
+		Table("operator_tokens").
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return internal.ErrRecordNoFound
+	}
+	return nil
+}
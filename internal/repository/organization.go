@@ -54,9 +54,17 @@ func (o *Organization) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organ
 func (o *Organization) Create(ctx context.Context, organization *domain.Organization) (*domain.Organization, error) {
 	model := organization.ToModel()
 
-	result := o.Repository.db.WithContext(ctx).Table("organizations").Create(model)
-	if result.Error != nil {
-		return nil, result.Error
+	err := o.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("organizations").Create(model)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityOrganization, *model.UUID, domain.AuditActionCreate,
+			nil, domain.FromModel(model))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return domain.FromModel(model), nil
@@ -66,41 +74,52 @@ func (o *Organization) Update(ctx context.Context, organization *domain.Organiza
 	model := organization.ToModel()
 
 	existingOrganization := &models.Organization{}
-	result := o.Repository.db.WithContext(ctx).Table("organizations").Where("uuid = ?", model.UUID).First(existingOrganization)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, internal.ErrRecordNoFound
+	err := o.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("organizations").Where("uuid = ?", model.UUID).First(existingOrganization)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return internal.ErrRecordNoFound
+			}
+			return result.Error
 		}
-		return nil, result.Error
-	}
+		before := domain.FromModel(existingOrganization)
 
-	existingOrganization.Name = model.Name
+		existingOrganization.Name = model.Name
+		existingOrganization.AllowedPhoneCountries = model.AllowedPhoneCountries
 
-	result = o.Repository.db.WithContext(ctx).Table("organizations").Save(existingOrganization)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, internal.ErrRecordNoFound
+		result = tx.Table("organizations").Save(existingOrganization)
+		if result.Error != nil {
+			return result.Error
 		}
-		return nil, result.Error
+
+		return writeAudit(ctx, tx, domain.AuditEntityOrganization, *existingOrganization.UUID, domain.AuditActionUpdate,
+			before, domain.FromModel(existingOrganization))
+	})
+	if err != nil {
+		return nil, err
 	}
 	return domain.FromModel(existingOrganization), nil
 }
 
 func (o *Organization) Delete(ctx context.Context, id uuid.UUID) error {
-	organization := &models.Organization{}
-	result := o.Repository.db.WithContext(ctx).Table("organizations").Where("uuid = ?", id).First(organization)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return internal.ErrRecordNoFound
+	return o.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		organization := &models.Organization{}
+		result := tx.Table("organizations").Where("uuid = ?", id).First(organization)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return internal.ErrRecordNoFound
+			}
+			return result.Error
 		}
-		return result.Error
-	}
 
-	result = o.Repository.db.WithContext(ctx).Table("organizations").Delete(organization)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
+		result = tx.Table("organizations").Delete(organization)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntityOrganization, id, domain.AuditActionDelete,
+			domain.FromModel(organization), nil)
+	})
 }
 
 func (o *Organization) FindByName(ctx context.Context, name string) (*domain.Organization, error) {
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"app_aggregator/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// sealPhone шифрует телефон заявки перед записью, в открытом виде он в базе не хранится
+func (r *Repository) sealPhone(model *models.LoanApplication) error {
+	if model.Phone == "" {
+		return nil
+	}
+
+	encrypted, err := r.phones.Encrypt(model.Phone)
+	if err != nil {
+		return err
+	}
+	model.PhoneEncrypted = encrypted
+	model.PhoneHash = r.phones.Index(model.Phone)
+	model.Phone = ""
+	return nil
+}
+
+// openPhone расшифровывает телефон загруженной заявки. У заявок, которые еще не зашифрованы
+// (см. EncryptPhones), телефон остается в колонке phone.
+func (r *Repository) openPhone(model *models.LoanApplication) error {
+	if model.PhoneEncrypted == "" {
+		return nil
+	}
+
+	phone, err := r.phones.Decrypt(model.PhoneEncrypted)
+	if err != nil {
+		return err
+	}
+	model.Phone = phone
+	return nil
+}
+
+// wherePhone ищет заявки с телефоном phone по слепому индексу
+func (r *Repository) wherePhone(query *gorm.DB, phone string) *gorm.DB {
+	return query.Where("(phone_hash = ? OR (phone_hash IS NULL AND phone = ?))", r.phones.Index(phone), phone)
+}
@@ -1,23 +1,31 @@
 package repository
 
 import (
+	"app_aggregator/internal/circuitbreaker"
+	"app_aggregator/internal/pii"
 	"app_aggregator/pkg/db"
+	"log/slog"
 
 	"gorm.io/gorm"
 )
 
 type Repository struct {
-	db        *gorm.DB
-	kassaDb   *gorm.DB
-	doverixDb *gorm.DB
-	deDb      *gorm.DB
+	db            *gorm.DB
+	clientSources *ClientSourceRegistry
+	phones        *pii.Cipher
 }
 
-func NewRepository(db *db.DB) *Repository {
-	return &Repository{
-		db:        db.PGDB,
-		kassaDb:   db.KassaDB,
-		doverixDb: db.DoverixDB,
-		deDb:      db.DEDB,
+func NewRepository(db *db.DB, breakerConfig *circuitbreaker.Config, phones *pii.Cipher, logger *slog.Logger) (*Repository, error) {
+	clientSources := NewClientSourceRegistry(breakerConfig, logger)
+	for _, source := range db.ClientSources {
+		if err := clientSources.Register(NewMSSQLClientSource(source.Name, source.OrganizationUUID, source.Timeout, source.DB)); err != nil {
+			return nil, err
+		}
 	}
+
+	return &Repository{
+		db:            db.PGDB,
+		clientSources: clientSources,
+		phones:        phones,
+	}, nil
 }
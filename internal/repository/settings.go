@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Settings struct {
+	Repository *Repository
+}
+
+func NewSettingsRepository(r *Repository) *Settings {
+	return &Settings{
+		Repository: r,
+	}
+}
+
+// GetAll возвращает настройки только действующих организаций: настройки удаленной организации
+// остаются в таблице, но участвовать в маршрутизации не должны
+func (s *Settings) GetAll(ctx context.Context) ([]*domain.Settings, error) {
+	var settings []*models.Settings
+	result := s.Repository.db.WithContext(ctx).
+		Table("settings").
+		Joins("JOIN organizations ON organizations.uuid = settings.organisation_uuid AND organizations.deleted_at IS NULL").
+		Preload("Organization").
+		Find(&settings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	domainSettings := make([]*domain.Settings, len(settings))
+	for i, setting := range settings {
+		domainSettings[i] = domain.SettingsFromModel(setting)
+	}
+
+	return domainSettings, nil
+}
+
+func (s *Settings) GetByOrganization(ctx context.Context, organizationID uuid.UUID) (*domain.Settings, error) {
+	settings, err := loadSettings(s.Repository.db.WithContext(ctx), organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.SettingsFromModel(settings), nil
+}
+
+// Save создает настройки организации или обновляет существующие, каждый раз с новой версией.
+// Если settings.Version задан, он должен совпадать с текущей версией, иначе ErrSettingsVersionConflict.
+func (s *Settings) Save(ctx context.Context, settings *domain.Settings) (*domain.Settings, error) {
+	var saved *models.Settings
+	err := s.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := loadSettings(tx.Clauses(clause.Locking{Strength: "UPDATE"}), settings.OrganizationUUID)
+		if err != nil && !errors.Is(err, internal.ErrRecordNoFound) {
+			return err
+		}
+		currentVersion := 0
+		if existing != nil {
+			currentVersion = existing.Version
+		}
+		// Version - версия, которую видел клиент: 0 только для первых настроек организации
+		if settings.Version == 0 && existing != nil {
+			return internal.ErrSettingsVersionRequired
+		}
+		if settings.Version != currentVersion {
+			return internal.ErrSettingsVersionConflict
+		}
+
+		action := domain.AuditActionUpdate
+		var before *domain.Settings
+		if existing == nil {
+			action = domain.AuditActionCreate
+			organizationUUID := settings.OrganizationUUID
+			existing = &models.Settings{
+				OrganisationUUID: &organizationUUID,
+			}
+		} else {
+			before = domain.SettingsFromModel(existing)
+		}
+
+		existing.NewClient = settings.NewClient
+		existing.PDN = settings.PDN
+		existing.HasDebt = settings.HasDebt
+		existing.Version = currentVersion + 1
+
+		result := tx.Table("settings").Omit(clause.Associations).Save(existing)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Table("settings_versions").Create(&models.SettingsVersion{
+			OrganisationUUID: existing.OrganisationUUID,
+			Version:          existing.Version,
+			NewClient:        existing.NewClient,
+			PDN:              existing.PDN,
+			HasDebt:          existing.HasDebt,
+			Actor:            auth.ActorFromContext(ctx),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		saved, err = loadSettings(tx, settings.OrganizationUUID)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, domain.AuditEntitySettings, *saved.UUID, action,
+			before, domain.SettingsFromModel(saved))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.SettingsFromModel(saved), nil
+}
+
+// Versions возвращает историю настроек организации, новые версии первыми
+func (s *Settings) Versions(ctx context.Context, organizationID uuid.UUID) ([]*domain.SettingsVersion, error) {
+	var versions []*models.SettingsVersion
+	result := s.Repository.db.WithContext(ctx).
+		Table("settings_versions").
+		Where("organisation_uuid = ?", organizationID).
+		Order("version DESC").
+		Find(&versions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	domainVersions := make([]*domain.SettingsVersion, len(versions))
+	for i, version := range versions {
+		domainVersions[i] = domain.SettingsVersionFromModel(version)
+	}
+
+	return domainVersions, nil
+}
+
+func loadSettings(db *gorm.DB, organizationID uuid.UUID) (*models.Settings, error) {
+	settings := &models.Settings{}
+	result := db.Table("settings").
+		Preload("Organization").
+		Where("organisation_uuid = ?", organizationID).
+		First(settings)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, internal.ErrRecordNoFound
+		}
+		return nil, result.Error
+	}
+	return settings, nil
+}
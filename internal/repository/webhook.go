@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Webhook struct {
+	Repository *Repository
+}
+
+func NewWebhookRepository(r *Repository) *Webhook {
+	return &Webhook{
+		Repository: r,
+	}
+}
+
+func (w *Webhook) CreateEndpoint(ctx context.Context, organizationID uuid.UUID, url, secret string, eventTypes []string) (*domain.WebhookEndpoint, error) {
+	model := &models.WebhookEndpoint{
+		OrganizationUUID: &organizationID,
+		URL:              url,
+		Secret:           secret,
+		EventTypes:       domain.JoinEventTypes(eventTypes),
+	}
+
+	result := w.Repository.db.WithContext(ctx).Table("webhook_endpoints").Omit("Organization").Create(model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return domain.WebhookEndpointFromModel(model), nil
+}
+
+func (w *Webhook) GetEndpoints(ctx context.Context, organizationID uuid.UUID) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*models.WebhookEndpoint
+	result := w.Repository.db.WithContext(ctx).
+		Table("webhook_endpoints").
+		Where("organization_uuid = ? AND deleted_at IS NULL", organizationID).
+		Order("created_at").
+		Find(&endpoints)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	domainEndpoints := make([]*domain.WebhookEndpoint, len(endpoints))
+	for i, endpoint := range endpoints {
+		domainEndpoints[i] = domain.WebhookEndpointFromModel(endpoint)
+	}
+
+	return domainEndpoints, nil
+}
+
+// DeleteEndpoint удаляет адрес и переводит его неотправленные доставки в dead
+func (w *Webhook) DeleteEndpoint(ctx context.Context, organizationID, id uuid.UUID) error {
+	return w.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		endpoint := &models.WebhookEndpoint{}
+		result := tx.Table("webhook_endpoints").
+			Where("uuid = ? AND organization_uuid = ?", id, organizationID).
+			First(endpoint)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return internal.ErrRecordNoFound
+			}
+			return result.Error
+		}
+
+		if err := tx.Table("webhook_endpoints").Delete(endpoint).Error; err != nil {
+			return err
+		}
+
+		return tx.Table("webhook_deliveries").
+			Where("endpoint_id = ? AND status = ?", endpoint.ID, domain.WebhookDeliveryPending).
+			Updates(map[string]interface{}{
+				"status":     domain.WebhookDeliveryDead,
+				"last_error": "webhook endpoint deleted",
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+// ClaimDeliveries забирает до limit доставок, время которых пришло, и откладывает их на lease.
+// Если отправка не завершится за lease (например, процесс упал), доставку заберет следующий проход.
+func (w *Webhook) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := w.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		result := tx.Raw(`
+			UPDATE webhook_deliveries
+			SET attempts = attempts + 1, next_attempt_at = ?, updated_at = now()
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN webhook_endpoints e ON e.id = d.endpoint_id AND e.deleted_at IS NULL
+				WHERE d.status = ? AND d.next_attempt_at <= now()
+				ORDER BY d.next_attempt_at
+				LIMIT ?
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING id`, time.Now().Add(lease), domain.WebhookDeliveryPending, limit).
+			Scan(&ids)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tx.Table("webhook_deliveries").
+			Preload("Event").
+			Preload("Endpoint").
+			Where("id IN ?", ids).
+			Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveriesFromModels(deliveries), nil
+}
+
+func (w *Webhook) MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) error {
+	now := time.Now()
+	return w.Repository.db.WithContext(ctx).
+		Table("webhook_deliveries").
+		Where("uuid = ?", id).
+		Updates(map[string]interface{}{
+			"status":           domain.WebhookDeliveryDelivered,
+			"last_status_code": statusCode,
+			"last_error":       nil,
+			"delivered_at":     now,
+			"updated_at":       now,
+		}).Error
+}
+
+// MarkFailed сохраняет результат неудачной попытки. Если nextAttemptAt не задан, доставка становится dead.
+func (w *Webhook) MarkFailed(ctx context.Context, id uuid.UUID, statusCode *int, lastError string, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+		"updated_at":       time.Now(),
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["status"] = domain.WebhookDeliveryDead
+	}
+
+	return w.Repository.db.WithContext(ctx).
+		Table("webhook_deliveries").
+		Where("uuid = ?", id).
+		Updates(updates).Error
+}
+
+func (w *Webhook) ListDeliveries(ctx context.Context, filter *domain.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	query := w.Repository.db.WithContext(ctx).Table("webhook_deliveries")
+	if filter.Status != "" {
+		query = query.Where("webhook_deliveries.status = ?", filter.Status)
+	}
+	if filter.OrganizationUUID != nil {
+		query = query.
+			Joins("JOIN webhook_endpoints ON webhook_endpoints.id = webhook_deliveries.endpoint_id").
+			Where("webhook_endpoints.organization_uuid = ?", *filter.OrganizationUUID)
+	}
+
+	var deliveries []*models.WebhookDelivery
+	result := query.
+		Preload("Event").
+		Preload("Endpoint", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("webhook_deliveries.updated_at DESC, webhook_deliveries.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return deliveriesFromModels(deliveries), nil
+}
+
+// RetryDelivery возвращает dead доставку в очередь с новым счетчиком попыток.
+// Доставки на удаленные адреса повторить нельзя.
+func (w *Webhook) RetryDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	err := w.Repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("webhook_deliveries").
+			Where("uuid = ? AND status = ?", id, domain.WebhookDeliveryDead).
+			Where("endpoint_id IN (SELECT id FROM webhook_endpoints WHERE deleted_at IS NULL)").
+			Updates(map[string]interface{}{
+				"status":          domain.WebhookDeliveryPending,
+				"attempts":        0,
+				"next_attempt_at": time.Now(),
+				"updated_at":      time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return internal.ErrRecordNoFound
+		}
+
+		return tx.Table("webhook_deliveries").
+			Preload("Event").
+			Preload("Endpoint").
+			Where("uuid = ?", id).
+			First(delivery).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.WebhookDeliveryFromModel(delivery), nil
+}
+
+func deliveriesFromModels(deliveries []*models.WebhookDelivery) []*domain.WebhookDelivery {
+	domainDeliveries := make([]*domain.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		domainDeliveries[i] = domain.WebhookDeliveryFromModel(delivery)
+	}
+	return domainDeliveries
+}
+
+// writeOutboxEvent пишет событие по заявке в outbox в переданной транзакции
+// и ставит в очередь доставки на webhook входящей и выдающей организаций.
+// Организациям нужен настоящий телефон клиента, поэтому он хранится в payload зашифрованным,
+// а расшифровывается WebhookDispatcher перед отправкой.
+func (r *Repository) writeOutboxEvent(tx *gorm.DB, eventType string, loanApplication *models.LoanApplication) error {
+	event := &models.OutboxEvent{
+		EventType:  eventType,
+		EntityUUID: loanApplication.UUID,
+		CreatedAt:  time.Now(),
+	}
+	eventUUID := uuid.New()
+	event.UUID = &eventUUID
+
+	data := domain.LoanApplicationFromModel(loanApplication)
+	if data.Phone != "" {
+		encrypted, err := r.phones.Encrypt(data.Phone)
+		if err != nil {
+			return err
+		}
+		data.Phone = encrypted
+	}
+
+	payload, err := json.Marshal(&domain.WebhookEvent{
+		ID:        eventUUID,
+		Type:      eventType,
+		CreatedAt: event.CreatedAt,
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	event.Payload = string(payload)
+
+	if err := tx.Table("outbox_events").Create(event).Error; err != nil {
+		return err
+	}
+
+	var endpoints []*models.WebhookEndpoint
+	result := tx.Table("webhook_endpoints").
+		Where("organization_uuid IN ? AND deleted_at IS NULL",
+			[]*uuid.UUID{loanApplication.IncomingOrganizationUuid, loanApplication.IssueOrganizationUuid}).
+		Find(&endpoints)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !domain.WebhookEndpointFromModel(endpoint).Subscribed(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			EventID:       event.ID,
+			EndpointID:    endpoint.ID,
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: event.CreatedAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return tx.Table("webhook_deliveries").Omit("Event", "Endpoint").Create(&deliveries).Error
+}
@@ -0,0 +1,45 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header - заголовок запроса и ответа с идентификатором запроса
+const Header = "X-Request-ID"
+
+// maxLength - длиннее клиентский идентификатор не принимается и заменяется своим
+const maxLength = 128
+
+type contextKey struct{}
+
+// New возвращает идентификатор клиента, если он допустим, иначе генерирует новый
+func New(clientID string) string {
+	if valid(clientID) {
+		return clientID
+	}
+	return uuid.NewString()
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// valid допускает только печатные ASCII символы, чтобы идентификатор можно было безопасно писать в логи
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
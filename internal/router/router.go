@@ -2,13 +2,14 @@ package router
 
 import (
 	"context"
-	"encoding/json"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"app_aggregator/internal/auth"
 	"app_aggregator/internal/domain"
 	"app_aggregator/internal/handlers"
+	"app_aggregator/internal/metrics"
 	"app_aggregator/internal/middleware"
 	"app_aggregator/internal/ratelimit"
 )
@@ -18,17 +19,45 @@ type HTTPServer struct {
 	logger *slog.Logger
 }
 
-func NewHTTPServer(
-	organizationService domain.OrganizationService,
-	loanApplicationService domain.LoanApplicationService,
-	logger *slog.Logger,
-) *HTTPServer {
-	mux := http.NewServeMux()
+// Services - набор сервисов, которые обслуживает HTTP сервер
+type Services struct {
+	Organization    domain.OrganizationService
+	Settings        domain.SettingsService
+	LoanApplication domain.LoanApplicationService
+	Client          domain.ClientService
+	Audit           domain.AuditService
+	APIKey          domain.APIKeyService
+	Operator        domain.OperatorService
+	Health          domain.HealthService
+	Idempotency     domain.IdempotencyService
+	Dedup           domain.DedupService
+	Webhook         domain.WebhookService
+	Import          domain.ImportService
+}
 
-	organizationHandler := handlers.NewHTTPOrganizationHandler(organizationService, logger)
-	loanApplicationHandler := handlers.NewHTTPLoanApplicationHandler(loanApplicationService, logger)
+func NewHTTPServer(services *Services, logger *slog.Logger) *HTTPServer {
+	mux := http.NewServeMux()
 
-	registerRoutes(mux, organizationHandler, loanApplicationHandler)
+	routes := &routes{
+		mux:          mux,
+		partnerAuth:  middleware.APIKeyAuth(services.APIKey, logger),
+		idempotency:  middleware.Idempotency(services.Idempotency, logger),
+		operatorAuth: middleware.OperatorAuth(services.Operator, logger),
+		logger:       logger,
+	}
+	registerRoutes(routes, &routeHandlers{
+		organization:    handlers.NewHTTPOrganizationHandler(services.Organization, logger),
+		settings:        handlers.NewHTTPSettingsHandler(services.Settings, logger),
+		loanApplication: handlers.NewHTTPLoanApplicationHandler(services.LoanApplication, logger),
+		client:          handlers.NewHTTPClientHandler(services.Client, logger),
+		audit:           handlers.NewHTTPAuditHandler(services.Audit, logger),
+		apiKey:          handlers.NewHTTPAPIKeyHandler(services.APIKey, logger),
+		operator:        handlers.NewHTTPOperatorHandler(services.Operator, logger),
+		health:          handlers.NewHTTPHealthHandler(services.Health, logger),
+		dedup:           handlers.NewHTTPDedupHandler(services.Dedup, logger),
+		webhook:         handlers.NewHTTPWebhookHandler(services.Webhook, logger),
+		importer:        handlers.NewHTTPImportHandler(services.Import, logger),
+	})
 
 	rateLimitConfig := &ratelimit.Config{
 		RequestsPerMinute: 100,
@@ -39,10 +68,13 @@ func NewHTTPServer(
 
 	handler := middleware.Chain(
 		mux,
+		middleware.RequestID(),
+		middleware.Tracing(),
 		middleware.Logger(logger),
 		middleware.RateLimitWithLogger(rateLimitConfig, logger),
 		middleware.CORS(),
 		middleware.Recovery(logger),
+		middleware.Metrics(),
 	)
 
 	server := &http.Server{
@@ -59,6 +91,24 @@ func NewHTTPServer(
 	}
 }
 
+// NewMetricsServer отдает метрики Prometheus на отдельном адресе: в них есть счетчики по маршрутам
+// и организациям, поэтому адрес не должен быть доступен снаружи
+func NewMetricsServer(addr string, logger *slog.Logger) *HTTPServer {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	return &HTTPServer{
+		server: &http.Server{
+			Addr:         addr,
+			Handler:      mux,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
 func (s *HTTPServer) Start() error {
 	s.logger.Info("Starting HTTP server", slog.String("addr", s.server.Addr))
 	return s.server.ListenAndServe()
@@ -69,26 +119,102 @@ func (s *HTTPServer) Shutdown(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
 }
 
-func registerRoutes(
-	mux *http.ServeMux,
-	orgHandler *handlers.HTTPOrganizationHandler,
-	loanHandler *handlers.HTTPLoanApplicationHandler,
-) {
-	mux.HandleFunc("GET /api/v1/organizations", orgHandler.GetAll)
-	mux.HandleFunc("GET /api/v1/organizations/{uuid}", orgHandler.GetByID)
-
-	mux.HandleFunc("POST /api/v1/admin/organizations", orgHandler.Create)
-	mux.HandleFunc("PATCH /api/v1/admin/organizations/{uuid}", orgHandler.Update)
-	mux.HandleFunc("DELETE /api/v1/admin/organizations/{uuid}", orgHandler.Delete)
-
-	mux.HandleFunc("GET /api/v1/loan_applications", loanHandler.GetAll)
-	mux.HandleFunc("GET /api/v1/loan_applications/{uuid}", loanHandler.GetByID)
-	mux.HandleFunc("POST /api/v1/loan_applications", loanHandler.Create)
-	mux.HandleFunc("PATCH /api/v1/loan_applications/{uuid}", loanHandler.Update)
-	mux.HandleFunc("DELETE /api/v1/loan_applications/{uuid}", loanHandler.Delete)
-
-	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
-	})
+type routeHandlers struct {
+	organization    *handlers.HTTPOrganizationHandler
+	settings        *handlers.HTTPSettingsHandler
+	loanApplication *handlers.HTTPLoanApplicationHandler
+	client          *handlers.HTTPClientHandler
+	audit           *handlers.HTTPAuditHandler
+	apiKey          *handlers.HTTPAPIKeyHandler
+	operator        *handlers.HTTPOperatorHandler
+	health          *handlers.HTTPHealthHandler
+	dedup           *handlers.HTTPDedupHandler
+	webhook         *handlers.HTTPWebhookHandler
+	importer        *handlers.HTTPImportHandler
+}
+
+type routes struct {
+	mux          *http.ServeMux
+	partnerAuth  func(http.Handler) http.Handler
+	idempotency  func(http.Handler) http.Handler
+	operatorAuth func(http.Handler) http.Handler
+	logger       *slog.Logger
+}
+
+// public регистрирует маршрут без аутентификации
+func (r *routes) public(pattern string, handler http.HandlerFunc) {
+	r.mux.HandleFunc(pattern, handler)
+}
+
+// partner регистрирует маршрут для организаций-партнеров с API ключом
+func (r *routes) partner(pattern string, handler http.HandlerFunc) {
+	r.mux.Handle(pattern, r.partnerAuth(handler))
+}
+
+// idempotent сохраняет ответ обработчика для повторов с тем же Idempotency-Key
+func (r *routes) idempotent(handler http.HandlerFunc) http.HandlerFunc {
+	return r.idempotency(handler).ServeHTTP
+}
+
+// operator регистрирует маршрут для операторов с правом permission
+func (r *routes) operator(pattern string, permission auth.Permission, handler http.HandlerFunc) {
+	r.mux.Handle(pattern, middleware.Chain(
+		handler,
+		r.operatorAuth,
+		middleware.RequirePermission(permission, r.logger),
+	))
+}
+
+func registerRoutes(r *routes, h *routeHandlers) {
+	r.public("POST /api/v1/auth/login", h.operator.Login)
+	r.mux.Handle("POST /api/v1/auth/logout", r.operatorAuth(http.HandlerFunc(h.operator.Logout)))
+
+	r.public("GET /api/v1/organizations", h.organization.GetAll)
+	r.public("GET /api/v1/organizations/{uuid}", h.organization.GetByID)
+
+	r.operator("POST /api/v1/admin/organizations", auth.PermissionOrganizationsWrite, h.organization.Create)
+	r.operator("PATCH /api/v1/admin/organizations/{uuid}", auth.PermissionOrganizationsWrite, h.organization.Update)
+	r.operator("DELETE /api/v1/admin/organizations/{uuid}", auth.PermissionOrganizationsWrite, h.organization.Delete)
+
+	r.operator("GET /api/v1/admin/organizations/{uuid}/settings", auth.PermissionSettingsRead, h.settings.Get)
+	r.operator("PUT /api/v1/admin/organizations/{uuid}/settings", auth.PermissionOrganizationsWrite, h.settings.Save)
+	r.operator("GET /api/v1/admin/organizations/{uuid}/settings/versions", auth.PermissionSettingsRead, h.settings.Versions)
+
+	r.operator("GET /api/v1/admin/organizations/{uuid}/api_keys", auth.PermissionAPIKeysManage, h.apiKey.GetByOrganization)
+	r.operator("POST /api/v1/admin/organizations/{uuid}/api_keys", auth.PermissionAPIKeysManage, h.apiKey.Issue)
+	r.operator("DELETE /api/v1/admin/organizations/{uuid}/api_keys/{key_uuid}", auth.PermissionAPIKeysManage, h.apiKey.Revoke)
+
+	r.operator("GET /api/v1/admin/organizations/{uuid}/dedup_policy", auth.PermissionOrganizationsWrite, h.dedup.GetPolicy)
+	r.operator("PUT /api/v1/admin/organizations/{uuid}/dedup_policy", auth.PermissionOrganizationsWrite, h.dedup.SavePolicy)
+
+	r.operator("GET /api/v1/admin/organizations/{uuid}/webhooks", auth.PermissionWebhooksManage, h.webhook.GetEndpoints)
+	r.operator("POST /api/v1/admin/organizations/{uuid}/webhooks", auth.PermissionWebhooksManage, h.webhook.CreateEndpoint)
+	r.operator("DELETE /api/v1/admin/organizations/{uuid}/webhooks/{webhook_uuid}", auth.PermissionWebhooksManage, h.webhook.DeleteEndpoint)
+	r.operator("GET /api/v1/admin/webhooks/deliveries", auth.PermissionWebhooksManage, h.webhook.ListDeliveries)
+	r.operator("POST /api/v1/admin/webhooks/deliveries/{uuid}/retry", auth.PermissionWebhooksManage, h.webhook.RetryDelivery)
+
+	r.operator("GET /api/v1/loan_applications", auth.PermissionLoanApplicationsRead, h.loanApplication.GetAll)
+	r.operator("GET /api/v1/loan_applications/export", auth.PermissionLoanApplicationsExport, h.loanApplication.Export)
+	r.operator("GET /api/v1/loan_applications/{uuid}", auth.PermissionLoanApplicationsRead, h.loanApplication.GetByID)
+	r.partner("POST /api/v1/loan_applications", r.idempotent(h.loanApplication.Create))
+	r.operator("PATCH /api/v1/loan_applications/{uuid}", auth.PermissionLoanApplicationsWrite, h.loanApplication.Update)
+	r.operator("DELETE /api/v1/loan_applications/{uuid}", auth.PermissionLoanApplicationsDelete, h.loanApplication.Delete)
+	r.operator("POST /api/v1/loan_applications/{uuid}/transitions", auth.PermissionLoanApplicationsWrite, h.loanApplication.Transition)
+	r.operator("GET /api/v1/loan_applications/{uuid}/duplicates", auth.PermissionLoanApplicationsRead, h.dedup.DuplicateChain)
+
+	r.operator("POST /api/v1/admin/loan_applications/import", auth.PermissionLoanApplicationsImport, h.importer.Import)
+	r.operator("GET /api/v1/admin/loan_applications/import/{uuid}", auth.PermissionLoanApplicationsImport, h.importer.GetJob)
+	r.operator("GET /api/v1/admin/loan_applications/import/{uuid}/rows", auth.PermissionLoanApplicationsImport, h.importer.ListRows)
+
+	r.operator("GET /api/v1/clients/{phone}/history", auth.PermissionClientsRead, h.client.GetHistory)
+	r.operator("GET /api/v1/admin/client_sources", auth.PermissionSourcesRead, h.client.GetSourcesHealth)
+
+	r.operator("GET /api/v1/admin/audit", auth.PermissionAuditRead, h.audit.List)
+
+	r.operator("GET /api/v1/admin/operators", auth.PermissionOperatorsManage, h.operator.GetAll)
+	r.operator("POST /api/v1/admin/operators", auth.PermissionOperatorsManage, h.operator.Create)
+
+	r.public("GET /livez", h.health.Live)
+	r.public("GET /readyz", h.health.Ready)
+	r.public("GET /health", h.health.Live)
 }
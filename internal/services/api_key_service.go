@@ -0,0 +1,92 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefix      = "agg_"
+	apiKeyRandomBytes = 32
+	apiKeyPrefixLen   = 12
+)
+
+type APIKeyService struct {
+	repo    domain.APIKeyRepository
+	orgRepo domain.OrganizationRepository
+}
+
+func NewAPIKeyService(repo domain.APIKeyRepository, orgRepo domain.OrganizationRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:    repo,
+		orgRepo: orgRepo,
+	}
+}
+
+func (s *APIKeyService) Issue(ctx context.Context, organizationID uuid.UUID, name string) (*domain.IssuedAPIKey, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, internal.ErrInvalidAPIKeyName
+	}
+
+	if _, err := s.orgRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey, err := s.repo.Create(ctx, organizationID, name, key[:apiKeyPrefixLen], hashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IssuedAPIKey{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+func (s *APIKeyService) GetByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*domain.APIKey, error) {
+	if _, err := s.orgRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByOrganization(ctx, organizationID)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, organizationID, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, organizationID, id)
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*domain.Organization, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, internal.ErrInvalidAPIKey
+	}
+
+	organization, err := s.repo.FindOrganizationByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, internal.ErrRecordNoFound) {
+			return nil, internal.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
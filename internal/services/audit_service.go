@@ -0,0 +1,38 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditService struct {
+	repo domain.AuditRepository
+}
+
+func NewAuditService(repo domain.AuditRepository) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+func (s *AuditService) List(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	switch filter.EntityType {
+	case "", domain.AuditEntityLoanApplication, domain.AuditEntityOrganization, domain.AuditEntitySettings:
+	default:
+		return nil, internal.ErrInvalidAuditFilter
+	}
+	if filter.Limit < 0 || filter.Offset < 0 || filter.Limit > maxAuditLimit {
+		return nil, internal.ErrInvalidAuditFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	return s.repo.List(ctx, filter)
+}
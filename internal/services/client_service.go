@@ -0,0 +1,24 @@
+package services
+
+import (
+	"app_aggregator/internal/domain"
+	"context"
+)
+
+type ClientService struct {
+	repo domain.ClientRepository
+}
+
+func NewClientService(repo domain.ClientRepository) *ClientService {
+	return &ClientService{
+		repo: repo,
+	}
+}
+
+func (s *ClientService) GetHistory(ctx context.Context, phone string) ([]*domain.ClientHistory, error) {
+	return s.repo.FindClientHistory(ctx, phone)
+}
+
+func (s *ClientService) GetSourcesHealth(ctx context.Context) ([]*domain.ClientSourceHealth, error) {
+	return s.repo.SourcesHealth(ctx)
+}
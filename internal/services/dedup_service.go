@@ -0,0 +1,53 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type DedupService struct {
+	repo             domain.DedupRepository
+	organizationRepo domain.OrganizationRepository
+}
+
+func NewDedupService(repo domain.DedupRepository, organizationRepo domain.OrganizationRepository) *DedupService {
+	return &DedupService{
+		repo:             repo,
+		organizationRepo: organizationRepo,
+	}
+}
+
+// GetPolicy возвращает политику организации, а если она не задана - политику по умолчанию
+func (s *DedupService) GetPolicy(ctx context.Context, organizationID uuid.UUID) (*domain.DedupPolicy, error) {
+	_, err := s.organizationRepo.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.repo.GetPolicy(ctx, organizationID)
+	if errors.Is(err, internal.ErrRecordNoFound) {
+		return domain.DefaultDedupPolicy(organizationID), nil
+	}
+	return policy, err
+}
+
+func (s *DedupService) SavePolicy(ctx context.Context, policy *domain.DedupPolicy) (*domain.DedupPolicy, error) {
+	if !policy.Valid() {
+		return nil, internal.ErrInvalidDedupPolicy
+	}
+
+	_, err := s.organizationRepo.GetByID(ctx, policy.OrganizationUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.SavePolicy(ctx, policy)
+}
+
+func (s *DedupService) DuplicateChain(ctx context.Context, loanApplicationID uuid.UUID) (*domain.DuplicateChain, error) {
+	return s.repo.DuplicateChain(ctx, loanApplicationID)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"app_aggregator/internal/domain"
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthDependency - внешняя зависимость, которую проверяет readiness
+type HealthDependency struct {
+	Name     string
+	Required bool
+	Ping     func(ctx context.Context) error
+}
+
+type HealthService struct {
+	dependencies []HealthDependency
+	timeout      time.Duration
+}
+
+func NewHealthService(dependencies []HealthDependency, timeout time.Duration) *HealthService {
+	return &HealthService{
+		dependencies: dependencies,
+		timeout:      timeout,
+	}
+}
+
+// Readiness опрашивает зависимости параллельно, каждую со своим таймаутом
+func (s *HealthService) Readiness(ctx context.Context) *domain.ReadinessReport {
+	results := make([]*domain.DependencyHealth, len(s.dependencies))
+	var wg sync.WaitGroup
+	for i, dependency := range s.dependencies {
+		wg.Add(1)
+		go func(i int, dependency HealthDependency) {
+			defer wg.Done()
+			results[i] = s.check(ctx, dependency)
+		}(i, dependency)
+	}
+	wg.Wait()
+
+	report := &domain.ReadinessReport{
+		Status:       domain.ReadinessOK,
+		Dependencies: results,
+	}
+	for _, result := range results {
+		if result.Status == domain.DependencyUp {
+			continue
+		}
+		if result.Required {
+			report.Status = domain.ReadinessUnavailable
+			break
+		}
+		report.Status = domain.ReadinessDegraded
+	}
+
+	return report
+}
+
+func (s *HealthService) check(ctx context.Context, dependency HealthDependency) *domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dependency.Ping(ctx)
+
+	result := &domain.DependencyHealth{
+		Name:      dependency.Name,
+		Status:    domain.DependencyUp,
+		Required:  dependency.Required,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = domain.DependencyDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyLockTimeout - через сколько незавершенный запрос считается брошенным
+	// (например, процесс упал), и ключ можно занять заново
+	idempotencyLockTimeout = time.Minute
+)
+
+type IdempotencyService struct {
+	repo      domain.IdempotencyRepository
+	retention time.Duration
+}
+
+func NewIdempotencyService(repo domain.IdempotencyRepository, retention time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo:      repo,
+		retention: retention,
+	}
+}
+
+// Begin занимает ключ для нового запроса и возвращает nil,
+// либо возвращает сохраненный ответ на такой же запрос
+func (s *IdempotencyService) Begin(ctx context.Context, organizationID uuid.UUID, key, requestHash string) (*domain.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, internal.ErrInvalidIdempotencyKey
+	}
+
+	record, reserved, err := s.repo.Reserve(ctx, organizationID, key, requestHash, time.Now().Add(s.retention))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, internal.ErrIdempotencyKeyReused
+	}
+	if record.Completed() {
+		return record, nil
+	}
+	if time.Since(record.CreatedAt) < idempotencyLockTimeout {
+		return nil, internal.ErrIdempotencyKeyInProgress
+	}
+
+	if err := s.repo.Release(ctx, organizationID, key); err != nil {
+		return nil, err
+	}
+	return s.Begin(ctx, organizationID, key, requestHash)
+}
+
+func (s *IdempotencyService) Complete(ctx context.Context, organizationID uuid.UUID, key string, statusCode int, body []byte) error {
+	return s.repo.Complete(ctx, organizationID, key, statusCode, body)
+}
+
+func (s *IdempotencyService) Release(ctx context.Context, organizationID uuid.UUID, key string) error {
+	return s.repo.Release(ctx, organizationID, key)
+}
+
+// RunCleanup периодически удаляет просроченные ключи, пока не отменен ctx
+func (s *IdempotencyService) RunCleanup(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteExpired(ctx)
+			if err != nil {
+				logger.Error("Failed to delete expired idempotency keys", slog.String("error", err.Error()))
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("Expired idempotency keys deleted", slog.Int64("count", deleted))
+			}
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/auth"
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/importer"
+	"app_aggregator/pkg/validators"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	minLoanApplicationValue = 1000
+	importBatchSize         = 25
+	// importStaleAfter - задача без обновлений дольше этого времени считается прерванной
+	importStaleAfter       = 10 * time.Minute
+	defaultImportRowsLimit = 100
+	maxImportRowsLimit     = 1000
+)
+
+// importSyncBudget - сколько запрос ждет обработки небольшого файла. Каждая строка может ждать таймаута
+// legacy источников, поэтому после бюджета обработка продолжается в фоне, а клиент получает 202.
+// Бюджет меньше WriteTimeout сервера.
+const importSyncBudget = 10 * time.Second
+
+// ImportService загружает заявки из файлов через ту же проверку, дедупликацию и маршрутизацию, что и Create.
+// Файлы до syncRows строк обрабатываются в запросе, большие - в фоне с сохранением прогресса.
+type ImportService struct {
+	repo             domain.ImportRepository
+	orgRepo          domain.OrganizationRepository
+	loanApplications domain.LoanApplicationService
+	maxRows          int
+	syncRows         int
+	logger           *slog.Logger
+}
+
+func NewImportService(
+	repo domain.ImportRepository,
+	orgRepo domain.OrganizationRepository,
+	loanApplications domain.LoanApplicationService,
+	maxRows, syncRows int,
+	logger *slog.Logger,
+) *ImportService {
+	return &ImportService{
+		repo:             repo,
+		orgRepo:          orgRepo,
+		loanApplications: loanApplications,
+		maxRows:          maxRows,
+		syncRows:         syncRows,
+		logger:           logger,
+	}
+}
+
+// Start разбирает файл и создает задачу импорта заявок от имени организации organizationID
+func (s *ImportService) Start(ctx context.Context, organizationID uuid.UUID, fileName string, file io.Reader, mapping domain.ImportColumnMapping) (*domain.ImportJob, error) {
+	format, err := importer.DetectFormat(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := s.orgRepo.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := importer.Parse(file, format, mapping, s.maxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.repo.CreateJob(ctx, &domain.ImportJob{
+		OrganizationUUID: organizationID,
+		FileName:         fileName,
+		Status:           domain.ImportJobStatusRunning,
+		TotalRows:        len(rows),
+		Actor:            auth.ActorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// фоновая обработка не должна прерываться вместе с запросом, но сохраняет оператора для аудита.
+	// Задачу меняет обработка, поэтому клиенту возвращается копия или состояние из базы.
+	started := *job
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.process(context.WithoutCancel(ctx), job, organization, rows)
+	}()
+	if len(rows) > s.syncRows {
+		return &started, nil
+	}
+
+	timer := time.NewTimer(importSyncBudget)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return s.repo.GetJob(context.WithoutCancel(ctx), job.UUID)
+}
+
+func (s *ImportService) GetJob(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	return s.repo.GetJob(ctx, id)
+}
+
+func (s *ImportService) ListRows(ctx context.Context, jobID uuid.UUID, filter *domain.ImportRowFilter) ([]*domain.ImportRowResult, error) {
+	switch filter.Status {
+	case "", domain.ImportRowCreated, domain.ImportRowDuplicate, domain.ImportRowInvalid, domain.ImportRowFailed:
+	default:
+		return nil, internal.ErrInvalidImportFilter
+	}
+	if filter.Limit < 0 || filter.Offset < 0 || filter.Limit > maxImportRowsLimit {
+		return nil, internal.ErrInvalidImportFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultImportRowsLimit
+	}
+
+	return s.repo.ListRows(ctx, jobID, filter)
+}
+
+// FailInterrupted завершает задачи, обработка которых остановилась вместе с процессом
+func (s *ImportService) FailInterrupted(ctx context.Context) (int64, error) {
+	return s.repo.FailStale(ctx, time.Now().Add(-importStaleAfter))
+}
+
+func (s *ImportService) process(ctx context.Context, job *domain.ImportJob, organization *domain.Organization, rows []domain.ImportRow) {
+	batch := make([]*domain.ImportRowResult, 0, importBatchSize)
+	for _, row := range rows {
+		result := s.importRow(ctx, organization, row)
+		job.Count(result)
+		batch = append(batch, result)
+
+		if len(batch) == importBatchSize {
+			if err := s.repo.SaveProgress(ctx, job, batch); err != nil {
+				s.fail(ctx, job, err)
+				return
+			}
+			batch = batch[:0]
+		}
+	}
+
+	finishedAt := time.Now()
+	job.Status = domain.ImportJobStatusCompleted
+	job.FinishedAt = &finishedAt
+	if err := s.repo.SaveProgress(ctx, job, batch); err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+
+	s.logger.Info("Loan application import completed",
+		slog.String("job_uuid", job.UUID.String()),
+		slog.Int("created", job.Created),
+		slog.Int("duplicates", job.Duplicates),
+		slog.Int("invalid", job.Invalid),
+		slog.Int("failed", job.Failed))
+}
+
+// fail отмечает задачу неудавшейся, если не удалось сохранить прогресс
+func (s *ImportService) fail(ctx context.Context, job *domain.ImportJob, err error) {
+	s.logger.Error("Failed to save import progress", slog.String("job_uuid", job.UUID.String()), slog.String("error", err.Error()))
+
+	finishedAt := time.Now()
+	job.Status = domain.ImportJobStatusFailed
+	job.Error = "failed to save import progress"
+	job.FinishedAt = &finishedAt
+	if err := s.repo.SaveProgress(ctx, job, nil); err != nil {
+		s.logger.Error("Failed to mark import failed", slog.String("job_uuid", job.UUID.String()), slog.String("error", err.Error()))
+	}
+}
+
+// importRow проверяет строку так же, как обработчик создания заявки, и создает заявку
+func (s *ImportService) importRow(ctx context.Context, organization *domain.Organization, row domain.ImportRow) *domain.ImportRowResult {
+	result := &domain.ImportRowResult{Line: row.Line}
+	invalid := func(reason string) *domain.ImportRowResult {
+		result.Status = domain.ImportRowInvalid
+		result.Reason = reason
+		return result
+	}
+
+	phone, err := validators.ParsePhone(row.Phone, organization.AllowedPhoneCountries...)
+	if errors.Is(err, internal.ErrPhoneCountryNotAllowed) {
+		return invalid(domain.ImportReasonPhoneCountryNotAllowed)
+	}
+	if err != nil {
+		return invalid(domain.ImportReasonInvalidPhone)
+	}
+	value, err := parseImportValue(row.Value)
+	if err != nil {
+		return invalid(domain.ImportReasonInvalidValue)
+	}
+	if value < minLoanApplicationValue {
+		return invalid(domain.ImportReasonValueTooLow)
+	}
+
+	app, err := s.loanApplications.Create(ctx, &domain.LoanApplication{
+		IncomingOrganizationName: organization.Name,
+		Value:                    value,
+		Phone:                    phone.E164,
+		PhoneCountry:             phone.Country,
+		PhoneType:                string(phone.Type),
+		Comment:                  row.Comment,
+	})
+	var duplicate *internal.DuplicateLoanApplicationError
+	switch {
+	case errors.As(err, &duplicate):
+		result.Status = domain.ImportRowDuplicate
+		result.Reason = domain.ImportReasonDuplicate
+		result.LoanApplicationUUID = &duplicate.OriginalUUID
+	case errors.Is(err, internal.ErrNoIssuingOrganization):
+		return invalid(domain.ImportReasonNoIssuingOrganization)
+	case err != nil:
+		s.logger.Error("Failed to import loan application",
+			slog.Int("line", row.Line),
+			slog.String("error", err.Error()))
+		result.Status = domain.ImportRowFailed
+		result.Reason = domain.ImportReasonInternalError
+	case app.Merged:
+		result.Status = domain.ImportRowDuplicate
+		result.Reason = domain.ImportReasonMerged
+		result.LoanApplicationUUID = &app.UUID
+	default:
+		result.Status = domain.ImportRowCreated
+		result.LoanApplicationUUID = &app.UUID
+	}
+
+	return result
+}
+
+// parseImportValue принимает суммы вида "50000", "50 000" и "50000,00"
+func parseImportValue(raw string) (int64, error) {
+	cleaned := strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(raw)
+	if whole, fraction, found := strings.Cut(cleaned, "."); found && strings.Trim(fraction, "0") == "" {
+		cleaned = whole
+	}
+	return strconv.ParseInt(cleaned, 10, 64)
+}
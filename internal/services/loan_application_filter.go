@@ -0,0 +1,50 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+)
+
+const (
+	defaultLoanApplicationsLimit = 50
+	maxLoanApplicationsLimit     = 500
+	exportLoanApplicationsBatch  = 1000
+)
+
+// normalizeLoanApplicationFilter проверяет фильтр и подставляет значения по умолчанию
+func normalizeLoanApplicationFilter(filter *domain.LoanApplicationFilter) error {
+	switch filter.Sort {
+	case "":
+		filter.Sort = domain.LoanApplicationSortCreatedAt
+	case domain.LoanApplicationSortCreatedAt, domain.LoanApplicationSortValue:
+	default:
+		return internal.ErrInvalidFilter
+	}
+
+	switch filter.Order {
+	case "":
+		filter.Order = domain.SortOrderDesc
+	case domain.SortOrderAsc, domain.SortOrderDesc:
+	default:
+		return internal.ErrInvalidFilter
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = defaultLoanApplicationsLimit
+	case filter.Limit < 0 || filter.Limit > maxLoanApplicationsLimit:
+		return internal.ErrInvalidFilter
+	}
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return internal.ErrInvalidFilter
+	}
+	if filter.MinValue != nil && filter.MaxValue != nil && *filter.MinValue > *filter.MaxValue {
+		return internal.ErrInvalidFilter
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && filter.CreatedFrom.After(*filter.CreatedTo) {
+		return internal.ErrInvalidFilter
+	}
+
+	return nil
+}
@@ -3,35 +3,153 @@ package services
 import (
 	"app_aggregator/internal"
 	"app_aggregator/internal/domain"
+	"app_aggregator/internal/metrics"
+	"app_aggregator/internal/tracing"
 	"context"
+	"slices"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// encryptPhonesBatch - сколько заявок перешифровывается в одной транзакции
+const encryptPhonesBatch = 500
+
 type LoanApplicationService struct {
-	repo domain.LoanApplicationRepository
+	repo             domain.LoanApplicationRepository
+	clientRepo       domain.ClientRepository
+	settingsRepo     domain.SettingsRepository
+	organizationRepo domain.OrganizationRepository
+	routing          *RoutingEngine
 }
 
-func NewLoanApplicationService(repo domain.LoanApplicationRepository) *LoanApplicationService {
+func NewLoanApplicationService(
+	repo domain.LoanApplicationRepository,
+	clientRepo domain.ClientRepository,
+	settingsRepo domain.SettingsRepository,
+	organizationRepo domain.OrganizationRepository,
+) *LoanApplicationService {
 	return &LoanApplicationService{
-		repo: repo,
+		repo:             repo,
+		clientRepo:       clientRepo,
+		settingsRepo:     settingsRepo,
+		organizationRepo: organizationRepo,
+		routing:          NewRoutingEngine(),
+	}
+}
+
+func (s *LoanApplicationService) GetAll(ctx context.Context, filter *domain.LoanApplicationFilter) (*domain.LoanApplicationPage, error) {
+	if err := normalizeLoanApplicationFilter(filter); err != nil {
+		return nil, err
 	}
+
+	return s.repo.GetAll(ctx, filter)
 }
 
-func (s *LoanApplicationService) GetAll(ctx context.Context) ([]*domain.LoanApplication, error) {
-	return s.repo.GetAll(ctx)
+// Export передает в fn все заявки, подходящие под фильтр, читая их пачками по keyset курсору,
+// поэтому выгрузка не держит всю выборку в памяти. Limit фильтра не учитывается.
+func (s *LoanApplicationService) Export(ctx context.Context, filter *domain.LoanApplicationFilter, fn func(*domain.LoanApplication) error) (err error) {
+	ctx, span := tracing.Start(ctx, "LoanApplicationService.Export")
+	defer func() { tracing.End(span, err) }()
+
+	filter.Limit = 0
+	if err := normalizeLoanApplicationFilter(filter); err != nil {
+		return err
+	}
+	filter.Limit = exportLoanApplicationsBatch
+
+	exported := 0
+	defer func() { span.SetAttributes(attribute.Int("exported", exported)) }()
+	for {
+		page, err := s.repo.GetAll(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, app := range page.Items {
+			if err := fn(app); err != nil {
+				return err
+			}
+			exported++
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
 }
 
 func (s *LoanApplicationService) GetByID(ctx context.Context, id uuid.UUID) (*domain.LoanApplication, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *LoanApplicationService) Create(ctx context.Context, app *domain.LoanApplication) (*domain.LoanApplication, error) {
-	if app.IncomingOrganizationName == "" {
+func (s *LoanApplicationService) Create(ctx context.Context, app *domain.LoanApplication) (_ *domain.LoanApplication, err error) {
+	ctx, span := tracing.Start(ctx, "LoanApplicationService.Create",
+		trace.WithAttributes(attribute.String("incoming_organization", app.IncomingOrganizationName)))
+	defer func() { tracing.End(span, err) }()
+
+	if app.IncomingOrganizationName == "" || app.Value < minLoanApplicationValue {
 		return nil, internal.ErrInvalidLoanApplication
 	}
 
-	return s.repo.Create(ctx, app)
+	// дубль отклоняется или объединяется до маршрутизации, чтобы не нагружать legacy источники
+	merged, err := s.repo.Deduplicate(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	if merged != nil {
+		return merged, nil
+	}
+
+	profile, err := s.clientProfile(ctx, app.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.route(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	app.IssueOrganizationName = decision.Organization.Name
+	app.Status = domain.LoanApplicationStatusRouted
+	app.RoutingReason = decision.Reason
+	app.UnknownSources = profile.UnknownSources()
+	app.SettingsVersion = &decision.SettingsVersion
+
+	span.SetAttributes(attribute.String("issue_organization", app.IssueOrganizationName))
+
+	created, err := s.repo.Create(ctx, app, decision)
+	if err != nil {
+		return nil, err
+	}
+	if !created.Merged {
+		metrics.LoanApplicationsCreated.WithLabelValues(created.IncomingOrganizationName, created.IssueOrganizationName).Inc()
+	}
+
+	return created, nil
+}
+
+func (s *LoanApplicationService) clientProfile(ctx context.Context, phone string) (_ *domain.ClientProfile, err error) {
+	ctx, span := tracing.Start(ctx, "LoanApplicationService.clientProfile")
+	defer func() { tracing.End(span, err) }()
+
+	histories, err := s.clientRepo.FindClientHistory(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewClientProfile(phone, histories), nil
+}
+
+func (s *LoanApplicationService) route(ctx context.Context, profile *domain.ClientProfile) (_ *domain.RoutingDecision, err error) {
+	ctx, span := tracing.Start(ctx, "LoanApplicationService.route")
+	defer func() { tracing.End(span, err) }()
+
+	settings, err := s.settingsRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.routing.Route(profile, settings)
 }
 
 func (s *LoanApplicationService) Update(ctx context.Context, id uuid.UUID, app *domain.LoanApplication) (*domain.LoanApplication, error) {
@@ -43,22 +161,96 @@ func (s *LoanApplicationService) Update(ctx context.Context, id uuid.UUID, app *
 	if app.IncomingOrganizationName != "" {
 		existing.IncomingOrganizationName = app.IncomingOrganizationName
 	}
-	if app.IssueOrganizationName != "" {
-		existing.IssueOrganizationName = app.IssueOrganizationName
-	}
 	if app.Value != 0 {
 		existing.Value = app.Value
 	}
 	if app.Phone != "" {
 		existing.Phone = app.Phone
+		existing.PhoneCountry = app.PhoneCountry
+		existing.PhoneType = app.PhoneType
 	}
 	if app.Comment != "" {
 		existing.Comment = app.Comment
 	}
 
+	if app.Phone != "" || app.IncomingOrganizationName != "" {
+		if err := s.checkPhoneCountry(ctx, existing); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.repo.Update(ctx, existing)
 }
 
+// checkPhoneCountry проверяет, что страна телефона разрешена входящей организации, как при создании заявки
+func (s *LoanApplicationService) checkPhoneCountry(ctx context.Context, app *domain.LoanApplication) error {
+	organization, err := s.organizationRepo.FindByName(ctx, app.IncomingOrganizationName)
+	if err != nil {
+		return err
+	}
+	if len(organization.AllowedPhoneCountries) > 0 && !slices.Contains(organization.AllowedPhoneCountries, app.PhoneCountry) {
+		return internal.ErrPhoneCountryNotAllowed
+	}
+	return nil
+}
+
+func (s *LoanApplicationService) Transition(ctx context.Context, id uuid.UUID, status domain.LoanApplicationStatus) (_ *domain.LoanApplication, err error) {
+	ctx, span := tracing.Start(ctx, "LoanApplicationService.Transition",
+		trace.WithAttributes(attribute.String("status", string(status))))
+	defer func() { tracing.End(span, err) }()
+
+	if !status.Valid() {
+		return nil, internal.ErrInvalidStatus
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransition(existing.Status, status) {
+		return nil, internal.ErrIllegalTransition
+	}
+
+	return s.repo.UpdateStatus(ctx, id, existing.Status, status)
+}
+
+// Reroute заново маршрутизирует заявку по текущим настройкам организаций.
+// Заявки, которые уже взяты в работу, перемаршрутизировать нельзя.
+func (s *LoanApplicationService) Reroute(ctx context.Context, id uuid.UUID) (_ *domain.LoanApplication, err error) {
+	ctx, span := tracing.Start(ctx, "LoanApplicationService.Reroute")
+	defer func() { tracing.End(span, err) }()
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch existing.Status {
+	case domain.LoanApplicationStatusNew, domain.LoanApplicationStatusRouted:
+	default:
+		return nil, internal.ErrRerouteNotAllowed
+	}
+
+	profile, err := s.clientProfile(ctx, existing.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.route(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.UpdateRouting(ctx, id, existing.Status, decision, profile.UnknownSources())
+}
+
+// EncryptPhones перешифровывает телефоны заявок текущим ключом: после первого запуска с шифрованием
+// и после смены ключа. Старый ключ можно удалять из конфигурации, когда метод вернул результат без ошибки.
+func (s *LoanApplicationService) EncryptPhones(ctx context.Context) (int, error) {
+	return s.repo.EncryptPhones(ctx, encryptPhonesBatch)
+}
+
 func (s *LoanApplicationService) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
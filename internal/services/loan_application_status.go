@@ -0,0 +1,35 @@
+package services
+
+import "app_aggregator/internal/domain"
+
+// loanApplicationTransitions - допустимые переходы статусов заявки
+var loanApplicationTransitions = map[domain.LoanApplicationStatus][]domain.LoanApplicationStatus{
+	domain.LoanApplicationStatusNew: {
+		domain.LoanApplicationStatusRouted,
+		domain.LoanApplicationStatusRejected,
+		domain.LoanApplicationStatusCancelled,
+	},
+	domain.LoanApplicationStatusRouted: {
+		domain.LoanApplicationStatusInReview,
+		domain.LoanApplicationStatusRejected,
+		domain.LoanApplicationStatusCancelled,
+	},
+	domain.LoanApplicationStatusInReview: {
+		domain.LoanApplicationStatusApproved,
+		domain.LoanApplicationStatusRejected,
+		domain.LoanApplicationStatusCancelled,
+	},
+	domain.LoanApplicationStatusApproved: {
+		domain.LoanApplicationStatusIssued,
+		domain.LoanApplicationStatusCancelled,
+	},
+}
+
+func canTransition(from, to domain.LoanApplicationStatus) bool {
+	for _, status := range loanApplicationTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"app_aggregator/internal/domain"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to domain.LoanApplicationStatus
+		want     bool
+	}{
+		{domain.LoanApplicationStatusNew, domain.LoanApplicationStatusRouted, true},
+		{domain.LoanApplicationStatusNew, domain.LoanApplicationStatusInReview, false},
+		{domain.LoanApplicationStatusRouted, domain.LoanApplicationStatusInReview, true},
+		{domain.LoanApplicationStatusRouted, domain.LoanApplicationStatusApproved, false},
+		{domain.LoanApplicationStatusInReview, domain.LoanApplicationStatusApproved, true},
+		{domain.LoanApplicationStatusInReview, domain.LoanApplicationStatusRejected, true},
+		{domain.LoanApplicationStatusInReview, domain.LoanApplicationStatusRouted, false},
+		{domain.LoanApplicationStatusApproved, domain.LoanApplicationStatusIssued, true},
+		{domain.LoanApplicationStatusApproved, domain.LoanApplicationStatusRejected, false},
+		{domain.LoanApplicationStatusIssued, domain.LoanApplicationStatusCancelled, false},
+		{domain.LoanApplicationStatusRejected, domain.LoanApplicationStatusRouted, false},
+		{domain.LoanApplicationStatusCancelled, domain.LoanApplicationStatusNew, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := canTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("canTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	operatorTokenBytes     = 32
+	minOperatorPasswordLen = 8
+)
+
+type OperatorService struct {
+	repo     domain.OperatorRepository
+	tokenTTL time.Duration
+}
+
+func NewOperatorService(repo domain.OperatorRepository, tokenTTL time.Duration) *OperatorService {
+	return &OperatorService{
+		repo:     repo,
+		tokenTTL: tokenTTL,
+	}
+}
+
+func (s *OperatorService) GetAll(ctx context.Context) ([]*domain.Operator, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *OperatorService) Create(ctx context.Context, login, password string, role domain.OperatorRole) (*domain.Operator, error) {
+	login = strings.TrimSpace(login)
+	if login == "" || len(password) < minOperatorPasswordLen || !role.Valid() {
+		return nil, internal.ErrInvalidOperator
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, login, string(passwordHash), role)
+}
+
+// EnsureAdmin создает администратора при первом запуске, если оператора с таким логином нет
+func (s *OperatorService) EnsureAdmin(ctx context.Context, login, password string) error {
+	_, _, err := s.repo.FindCredentials(ctx, login)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, internal.ErrRecordNoFound) {
+		return err
+	}
+
+	_, err = s.Create(ctx, login, password, domain.OperatorRoleAdmin)
+	return err
+}
+
+func (s *OperatorService) Login(ctx context.Context, login, password string) (*domain.OperatorToken, error) {
+	operator, passwordHash, err := s.repo.FindCredentials(ctx, login)
+	if err != nil {
+		if errors.Is(err, internal.ErrRecordNoFound) {
+			return nil, internal.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)); err != nil {
+		return nil, internal.ErrInvalidCredentials
+	}
+
+	secret := make([]byte, operatorTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	expiresAt := time.Now().Add(s.tokenTTL)
+
+	if err := s.repo.CreateToken(ctx, operator.UUID, hashOperatorToken(token), expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &domain.OperatorToken{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		Role:      operator.Role,
+	}, nil
+}
+
+func (s *OperatorService) Logout(ctx context.Context, token string) error {
+	return s.repo.RevokeToken(ctx, hashOperatorToken(token))
+}
+
+func (s *OperatorService) Authenticate(ctx context.Context, token string) (*domain.Operator, error) {
+	operator, err := s.repo.FindByTokenHash(ctx, hashOperatorToken(token))
+	if err != nil {
+		if errors.Is(err, internal.ErrRecordNoFound) {
+			return nil, internal.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return operator, nil
+}
+
+func hashOperatorToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"app_aggregator/internal"
 	"app_aggregator/internal/domain"
+	"app_aggregator/pkg/validators"
 	"context"
 
 	"github.com/google/uuid"
@@ -30,17 +31,28 @@ func (s *OrganizationService) Create(ctx context.Context, org *domain.Organizati
 	if org.Name == "" {
 		return nil, internal.ErrInvalidOrganizationName
 	}
+	if err := validatePhoneCountries(org.AllowedPhoneCountries); err != nil {
+		return nil, err
+	}
 
 	return s.repo.Create(ctx, org)
 }
 
 func (s *OrganizationService) Update(ctx context.Context, id uuid.UUID, org *domain.Organization) (*domain.Organization, error) {
+	if err := validatePhoneCountries(org.AllowedPhoneCountries); err != nil {
+		return nil, err
+	}
+
 	existing, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	existing.Name = org.Name
+	// nil - список стран не передан и не меняется
+	if org.AllowedPhoneCountries != nil {
+		existing.AllowedPhoneCountries = org.AllowedPhoneCountries
+	}
 
 	return s.repo.Update(ctx, existing)
 }
@@ -53,3 +65,12 @@ func (s *OrganizationService) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return s.repo.Delete(ctx, id)
 }
+
+func validatePhoneCountries(countries []string) error {
+	for _, country := range countries {
+		if !validators.SupportedPhoneCountry(country) {
+			return internal.ErrInvalidPhoneCountries
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RoutingEngine выбирает организацию-выдачу по настройкам организаций и профилю клиента.
+// Организации проверяются в порядке имени, выигрывает первая подходящая.
+type RoutingEngine struct{}
+
+func NewRoutingEngine() *RoutingEngine {
+	return &RoutingEngine{}
+}
+
+func (e *RoutingEngine) Route(profile *domain.ClientProfile, settings []*domain.Settings) (*domain.RoutingDecision, error) {
+	candidates := make([]*domain.Settings, len(settings))
+	copy(candidates, settings)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].OrganizationName != candidates[j].OrganizationName {
+			return candidates[i].OrganizationName < candidates[j].OrganizationName
+		}
+		return candidates[i].OrganizationUUID.String() < candidates[j].OrganizationUUID.String()
+	})
+
+	rejected := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if reason := e.rejectReason(profile, candidate); reason != "" {
+			rejected = append(rejected, fmt.Sprintf("%s: %s", candidate.OrganizationName, reason))
+			continue
+		}
+
+		organizationUUID := candidate.OrganizationUUID
+		reason := fmt.Sprintf("%s: %s", candidate.OrganizationName, e.matchReason(profile, candidate))
+		if len(rejected) > 0 {
+			reason += "; rejected " + strings.Join(rejected, ", ")
+		}
+		if unknown := profile.UnknownSources(); len(unknown) > 0 {
+			reason += "; unknown sources " + strings.Join(unknown, ", ")
+		}
+
+		return &domain.RoutingDecision{
+			Organization: &domain.Organization{
+				UUID: &organizationUUID,
+				Name: candidate.OrganizationName,
+			},
+			Reason:          reason,
+			SettingsVersion: candidate.Version,
+		}, nil
+	}
+
+	return nil, internal.ErrNoIssuingOrganization
+}
+
+// rejectReason - почему организация не подходит. Если часть баз не ответила, клиент считается
+// существующим и с действующим займом: организации без займов у клиента его не получат.
+func (e *RoutingEngine) rejectReason(profile *domain.ClientProfile, settings *domain.Settings) string {
+	switch {
+	case profile.IsNewClient() && !settings.NewClient:
+		return "new clients are not accepted"
+	case profile.HasActiveLoan() && !settings.HasDebt && profile.Incomplete():
+		return "active loans cannot be ruled out"
+	case profile.HasActiveLoan() && !settings.HasDebt:
+		return "clients with active loans are not accepted"
+	case settings.PDN > 0 && profile.Pdn() > float64(settings.PDN):
+		return fmt.Sprintf("pdn %g exceeds limit %d", profile.Pdn(), settings.PDN)
+	}
+	return ""
+}
+
+func (e *RoutingEngine) matchReason(profile *domain.ClientProfile, settings *domain.Settings) string {
+	parts := make([]string, 0, 3)
+	switch {
+	case profile.IsNewClient():
+		parts = append(parts, "new client")
+	case profile.Incomplete():
+		parts = append(parts, "incomplete history")
+	default:
+		parts = append(parts, "existing client")
+	}
+	if profile.HasActiveLoan() && !profile.Incomplete() {
+		parts = append(parts, "has active loan")
+	}
+	if settings.PDN > 0 {
+		parts = append(parts, fmt.Sprintf("pdn %g <= %d", profile.Pdn(), settings.PDN))
+	}
+	return "matched (" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func routingSettings(name string, newClient, hasDebt bool, pdn int64) *domain.Settings {
+	return &domain.Settings{
+		OrganizationUUID: uuid.New(),
+		OrganizationName: name,
+		NewClient:        newClient,
+		HasDebt:          hasDebt,
+		PDN:              pdn,
+		Version:          1,
+	}
+}
+
+func TestRoutingEngineRoute(t *testing.T) {
+	notFound := &domain.ClientHistory{Status: domain.ClientLookupNotFound, Source: "alpha"}
+	withLoans := &domain.ClientHistory{Status: domain.ClientLookupFound, Source: "alpha", HasLoans: true}
+	withActiveLoan := &domain.ClientHistory{Status: domain.ClientLookupFound, Source: "alpha", HasLoans: true, HasActiveLoan: true}
+	withPdn := &domain.ClientHistory{Status: domain.ClientLookupFound, Source: "alpha", HasLoans: true, LastPdn: "55.5"}
+	withFractionalPdn := &domain.ClientHistory{Status: domain.ClientLookupFound, Source: "alpha", HasLoans: true, LastPdn: "45.9"}
+	withPdnAtLimit := &domain.ClientHistory{Status: domain.ClientLookupFound, Source: "alpha", HasLoans: true, LastPdn: "45.0"}
+	unknown := &domain.ClientHistory{Status: domain.ClientLookupUnknown, Source: "beta"}
+
+	tests := []struct {
+		name      string
+		histories []*domain.ClientHistory
+		settings  []*domain.Settings
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "first matching organization by name",
+			histories: []*domain.ClientHistory{notFound},
+			settings: []*domain.Settings{
+				routingSettings("Бета", true, true, 0),
+				routingSettings("Альфа", true, true, 0),
+			},
+			want: "Альфа",
+		},
+		{
+			name:      "new client skips organizations without new clients",
+			histories: []*domain.ClientHistory{notFound},
+			settings: []*domain.Settings{
+				routingSettings("A", false, true, 0),
+				routingSettings("B", true, false, 0),
+			},
+			want: "B",
+		},
+		{
+			name:      "existing client without active loan",
+			histories: []*domain.ClientHistory{withLoans},
+			settings: []*domain.Settings{
+				routingSettings("A", true, false, 0),
+			},
+			want: "A",
+		},
+		{
+			name:      "active loan skips organizations without debt",
+			histories: []*domain.ClientHistory{withActiveLoan},
+			settings: []*domain.Settings{
+				routingSettings("A", true, false, 0),
+				routingSettings("B", false, true, 0),
+			},
+			want: "B",
+		},
+		{
+			name:      "pdn above limit",
+			histories: []*domain.ClientHistory{withPdn},
+			settings: []*domain.Settings{
+				routingSettings("A", true, true, 50),
+				routingSettings("B", true, true, 60),
+			},
+			want: "B",
+		},
+		{
+			name:      "fractional pdn above limit is not truncated",
+			histories: []*domain.ClientHistory{withFractionalPdn},
+			settings: []*domain.Settings{
+				routingSettings("A", true, true, 45),
+				routingSettings("B", true, true, 46),
+			},
+			want: "B",
+		},
+		{
+			name:      "pdn equal to limit",
+			histories: []*domain.ClientHistory{withPdnAtLimit},
+			settings: []*domain.Settings{
+				routingSettings("A", true, true, 45),
+			},
+			want: "A",
+		},
+		{
+			name:      "unknown source is not a new client",
+			histories: []*domain.ClientHistory{notFound, unknown},
+			settings: []*domain.Settings{
+				routingSettings("A", true, false, 0),
+				routingSettings("B", false, true, 0),
+			},
+			want: "B",
+		},
+		{
+			name:      "unknown source rules out organizations without debt",
+			histories: []*domain.ClientHistory{notFound, unknown},
+			settings: []*domain.Settings{
+				routingSettings("A", true, false, 0),
+			},
+			wantErr: internal.ErrNoIssuingOrganization,
+		},
+		{
+			name:      "no settings",
+			histories: []*domain.ClientHistory{notFound},
+			wantErr:   internal.ErrNoIssuingOrganization,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := domain.NewClientProfile("+79123456789", tt.histories)
+			decision, err := NewRoutingEngine().Route(profile, tt.settings)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Route() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Route() error = %v", err)
+			}
+			if decision.Organization.Name != tt.want {
+				t.Errorf("Route() organization = %q, want %q (reason: %s)", decision.Organization.Name, tt.want, decision.Reason)
+			}
+			if decision.SettingsVersion != 1 {
+				t.Errorf("Route() settings version = %d, want 1", decision.SettingsVersion)
+			}
+		})
+	}
+}
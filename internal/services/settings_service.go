@@ -0,0 +1,54 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const maxSettingsPDN = 80
+
+type SettingsService struct {
+	repo             domain.SettingsRepository
+	organizationRepo domain.OrganizationRepository
+}
+
+func NewSettingsService(repo domain.SettingsRepository, organizationRepo domain.OrganizationRepository) *SettingsService {
+	return &SettingsService{
+		repo:             repo,
+		organizationRepo: organizationRepo,
+	}
+}
+
+func (s *SettingsService) GetAll(ctx context.Context) ([]*domain.Settings, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *SettingsService) GetByOrganization(ctx context.Context, organizationID uuid.UUID) (*domain.Settings, error) {
+	return s.repo.GetByOrganization(ctx, organizationID)
+}
+
+// Save сохраняет новую версию настроек. Заявки хранят версию, по которой были маршрутизированы,
+// и новые настройки применяются к ним только при явной перемаршрутизации.
+func (s *SettingsService) Save(ctx context.Context, settings *domain.Settings) (*domain.Settings, error) {
+	if settings.PDN < 0 || settings.PDN > maxSettingsPDN || settings.Version < 0 {
+		return nil, internal.ErrInvalidSettings
+	}
+
+	_, err := s.organizationRepo.GetByID(ctx, settings.OrganizationUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Save(ctx, settings)
+}
+
+func (s *SettingsService) Versions(ctx context.Context, organizationID uuid.UUID) ([]*domain.SettingsVersion, error) {
+	if _, err := s.organizationRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Versions(ctx, organizationID)
+}
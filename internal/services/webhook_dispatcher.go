@@ -0,0 +1,198 @@
+package services
+
+import (
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/metrics"
+	"app_aggregator/internal/pii"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	webhookBaseBackoff = 10 * time.Second
+	webhookMaxBackoff  = time.Hour
+	webhookErrorLimit  = 1000
+)
+
+// Заголовки запроса на webhook
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// WebhookDispatcher отправляет накопленные в outbox события на webhook организаций
+type WebhookDispatcher struct {
+	repo        domain.WebhookRepository
+	phones      *pii.Cipher
+	client      *http.Client
+	maxAttempts int
+	batchSize   int
+	logger      *slog.Logger
+}
+
+func NewWebhookDispatcher(repo domain.WebhookRepository, phones *pii.Cipher, timeout time.Duration, maxAttempts, batchSize int, logger *slog.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:        repo,
+		phones:      phones,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		batchSize:   batchSize,
+		logger:      logger,
+	}
+}
+
+// Run отправляет события каждые interval, пока не отменен ctx
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Dispatch(ctx); err != nil {
+				d.logger.Error("Failed to dispatch webhooks", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// Dispatch забирает пачки доставок, пока они есть, и отправляет их
+func (d *WebhookDispatcher) Dispatch(ctx context.Context) error {
+	// пока идет отправка, доставка не должна достаться другому экземпляру
+	lease := d.client.Timeout*time.Duration(d.batchSize) + time.Minute
+
+	for ctx.Err() == nil {
+		deliveries, err := d.repo.ClaimDeliveries(ctx, d.batchSize, lease)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			d.deliver(ctx, delivery)
+		}
+		if len(deliveries) < d.batchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	statusCode, err := d.send(ctx, delivery)
+	if err == nil {
+		metrics.WebhookDeliveries.WithLabelValues(delivery.EventType, metrics.WebhookDelivered).Inc()
+		if err := d.repo.MarkDelivered(ctx, delivery.UUID, *statusCode); err != nil {
+			d.logger.Error("Failed to mark webhook delivered", slog.String("delivery_uuid", delivery.UUID.String()), slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	var nextAttemptAt *time.Time
+	result := metrics.WebhookDead
+	if delivery.Attempts < d.maxAttempts {
+		next := time.Now().Add(webhookBackoff(delivery.Attempts))
+		nextAttemptAt = &next
+		result = metrics.WebhookFailed
+	}
+	metrics.WebhookDeliveries.WithLabelValues(delivery.EventType, result).Inc()
+
+	d.logger.Warn("Webhook delivery failed",
+		slog.String("delivery_uuid", delivery.UUID.String()),
+		slog.String("url", delivery.URL),
+		slog.Int("attempt", delivery.Attempts),
+		slog.String("result", result),
+		slog.String("error", err.Error()))
+
+	lastError := err.Error()
+	if len(lastError) > webhookErrorLimit {
+		lastError = lastError[:webhookErrorLimit]
+	}
+	if err := d.repo.MarkFailed(ctx, delivery.UUID, statusCode, lastError, nextAttemptAt); err != nil {
+		d.logger.Error("Failed to mark webhook failed", slog.String("delivery_uuid", delivery.UUID.String()), slog.String("error", err.Error()))
+	}
+}
+
+// send отправляет событие, успехом считается любой 2xx ответ
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *domain.WebhookDelivery) (*int, error) {
+	payload, err := d.openPayload(delivery.Payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, "t="+timestamp+",v1="+SignWebhook(delivery.Secret, timestamp, payload))
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookIDHeader, delivery.EventUUID.String())
+	req.Header.Set(WebhookDeliveryHeader, delivery.UUID.String())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		return &statusCode, fmt.Errorf("unexpected status code %d", statusCode)
+	}
+	return &statusCode, nil
+}
+
+// openPayload расшифровывает телефон заявки в событии из outbox. События, записанные до шифрования
+// payload, содержат маску телефона и отправляются как есть.
+func (d *WebhookDispatcher) openPayload(payload []byte) ([]byte, error) {
+	app := &domain.LoanApplication{}
+	event := &domain.WebhookEvent{Data: app}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if !pii.IsEncrypted(app.Phone) {
+		return payload, nil
+	}
+
+	phone, err := d.phones.Decrypt(app.Phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook payload: %w", err)
+	}
+	app.Phone = phone
+	return json.Marshal(event)
+}
+
+// SignWebhook - HMAC-SHA256 от "timestamp.body" в hex. Получатель проверяет подпись тем же секретом.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff - задержка перед следующей попыткой: 10s, 20s, 40s... не больше часа
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff
+}
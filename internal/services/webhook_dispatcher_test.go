@@ -0,0 +1,50 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"app_aggregator/internal/domain"
+	"app_aggregator/internal/pii"
+
+	"github.com/google/uuid"
+)
+
+func TestWebhookDispatcherOpenPayload(t *testing.T) {
+	phones, err := pii.NewCipher(map[string][]byte{"v1": bytes.Repeat([]byte{1}, 32)}, "v1", bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	dispatcher := NewWebhookDispatcher(nil, phones, time.Second, 1, 1, slog.Default())
+
+	encrypted, err := phones.Encrypt("+79123456789")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	stored, _ := json.Marshal(&domain.WebhookEvent{
+		ID:   uuid.New(),
+		Type: domain.EventLoanApplicationCreated,
+		Data: &domain.LoanApplication{Phone: encrypted, Value: 1000},
+	})
+
+	payload, err := dispatcher.openPayload(stored)
+	if err != nil {
+		t.Fatalf("openPayload() error = %v", err)
+	}
+	app := &domain.LoanApplication{}
+	if err := json.Unmarshal(payload, &domain.WebhookEvent{Data: app}); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if app.Phone != "+79123456789" || app.Value != 1000 {
+		t.Errorf("openPayload() data = %+v, want decrypted phone", app)
+	}
+
+	// события до шифрования payload хранят маску и отправляются без изменений
+	masked := []byte(`{"id":"` + uuid.NewString() + `","data":{"phone":"+7912***6789"}}`)
+	if payload, err := dispatcher.openPayload(masked); err != nil || !bytes.Equal(payload, masked) {
+		t.Errorf("openPayload(masked) = %s, %v, want unchanged", payload, err)
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"app_aggregator/internal"
+	"app_aggregator/internal/domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookSecretPrefix      = "whsec_"
+	webhookSecretRandomBytes = 24
+	defaultDeliveryLimit     = 100
+	maxDeliveryLimit         = 1000
+)
+
+type WebhookService struct {
+	repo    domain.WebhookRepository
+	orgRepo domain.OrganizationRepository
+}
+
+func NewWebhookService(repo domain.WebhookRepository, orgRepo domain.OrganizationRepository) *WebhookService {
+	return &WebhookService{
+		repo:    repo,
+		orgRepo: orgRepo,
+	}
+}
+
+// CreateEndpoint регистрирует адрес организации и выпускает секрет для подписи запросов
+func (s *WebhookService) CreateEndpoint(ctx context.Context, organizationID uuid.UUID, endpointURL string, eventTypes []string) (*domain.IssuedWebhookEndpoint, error) {
+	parsed, err := url.Parse(endpointURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, internal.ErrInvalidWebhookEndpoint
+	}
+	for _, eventType := range eventTypes {
+		if !domain.ValidEventType(eventType) {
+			return nil, internal.ErrInvalidWebhookEndpoint
+		}
+	}
+
+	if _, err := s.orgRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, webhookSecretRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	secret := webhookSecretPrefix + hex.EncodeToString(random)
+
+	endpoint, err := s.repo.CreateEndpoint(ctx, organizationID, endpointURL, secret, eventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.IssuedWebhookEndpoint{
+		WebhookEndpoint: endpoint,
+		Secret:          secret,
+	}, nil
+}
+
+func (s *WebhookService) GetEndpoints(ctx context.Context, organizationID uuid.UUID) ([]*domain.WebhookEndpoint, error) {
+	if _, err := s.orgRepo.GetByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetEndpoints(ctx, organizationID)
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, organizationID, id uuid.UUID) error {
+	return s.repo.DeleteEndpoint(ctx, organizationID, id)
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter *domain.WebhookDeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	switch filter.Status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDead:
+	default:
+		return nil, internal.ErrInvalidDeliveryFilter
+	}
+	if filter.Limit < 0 || filter.Offset < 0 || filter.Limit > maxDeliveryLimit {
+		return nil, internal.ErrInvalidDeliveryFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultDeliveryLimit
+	}
+
+	return s.repo.ListDeliveries(ctx, filter)
+}
+
+func (s *WebhookService) RetryDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	return s.repo.RetryDelivery(ctx, id)
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	gormSpanKey = "tracing:span"
+
+	// SourceAttribute - имя базы данных (pg или имя legacy источника), к которой относится спан
+	SourceAttribute = attribute.Key("app_aggregator.db.source")
+)
+
+// GormPlugin создает спан на каждый запрос GORM. Спан становится дочерним
+// для спана из контекста запроса, поэтому запросы нужно выполнять через WithContext.
+type GormPlugin struct {
+	source string
+	system attribute.KeyValue
+}
+
+func NewGormPlugin(source string, system attribute.KeyValue) *GormPlugin {
+	return &GormPlugin{
+		source: source,
+		system: system,
+	}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", p.before("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", p.after),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", p.before("query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", p.after),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", p.before("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", p.after),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", p.before("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", p.after),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", p.before("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", p.after),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", p.before("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", p.after),
+	}
+	for _, err := range registrations {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *GormPlugin) before(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		p.startSpan(tx, operation)
+	}
+}
+
+// startSpan не создает корневых спанов: запросы вне трассируемого контекста (миграции, CLI) пропускаются
+func (p *GormPlugin) startSpan(tx *gorm.DB, operation string) {
+	ctx := tx.Statement.Context
+	if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return
+	}
+
+	_, span := Start(ctx, p.source+" "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			p.system,
+			SourceAttribute.String(p.source),
+			semconv.DBOperationName(operation),
+		),
+	)
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+	tx.InstanceSet(gormSpanKey, span)
+}
+
+func (p *GormPlugin) after(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	// в SQL остаются плейсхолдеры, значения параметров (телефоны) в спан не попадают
+	span.SetAttributes(
+		semconv.DBQueryText(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+	span.End()
+}
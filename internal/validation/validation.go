@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"app_aggregator/internal/problem"
+	"app_aggregator/pkg/validators"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// nameRe - буквы, цифры, пробелы и знаки, которые встречаются в названиях организаций.
+// Название начинается с буквы или цифры и не заканчивается пробелом.
+var nameRe = regexp.MustCompile(`^[\p{L}\p{N}](?:[\p{L}\p{N} .,'"«»&()№-]*[^\s])?$`)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// В ошибках поля называются так же, как в JSON запроса
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	v.RegisterValidation("name", func(fl validator.FieldLevel) bool {
+		name := fl.Field().String()
+		return nameRe.MatchString(name) && !strings.Contains(name, "  ")
+	})
+	v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return validators.ValidPhone(fl.Field().String())
+	})
+	v.RegisterValidation("phone_country", func(fl validator.FieldLevel) bool {
+		return validators.SupportedPhoneCountry(fl.Field().String())
+	})
+
+	return v
+}
+
+// Struct проверяет структуру по тегам validate и возвращает все нарушения сразу
+// в виде *problem.ValidationError
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	result := &problem.ValidationError{Errors: make([]problem.FieldError, 0, len(fieldErrors))}
+	for _, fe := range fieldErrors {
+		result.Errors = append(result.Errors, problem.FieldError{
+			Field:   fieldPath(fe),
+			Message: message(fe),
+		})
+	}
+	return result
+}
+
+// fieldPath убирает из пути имя корневой структуры: CreateLoanApplicationRequest.phone -> phone
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "min":
+		if isString(fe) {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if isString(fe) {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "isdefault":
+		return "is read-only"
+	case "url":
+		return "must be a valid URL"
+	case "name":
+		return "must start with a letter or digit and contain only letters, digits, single spaces and .,'\"«»&()№- characters"
+	case "phone":
+		return "invalid phone number format"
+	case "phone_country":
+		return "must be one of: " + strings.Join(validators.PhoneCountries(), ", ")
+	default:
+		return fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+}
+
+func isString(fe validator.FieldError) bool {
+	return fe.Kind() == reflect.String
+}
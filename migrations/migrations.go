@@ -1,57 +1,329 @@
 package migrations
 
 import (
-	"app_aggregator/internal/config"
-	"app_aggregator/internal/models"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// advisoryLockKey - ключ pg_advisory_lock, не дает нескольким репликам мигрировать одновременно
+const advisoryLockKey int64 = 0x6170705f61676772
 
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var (
+	ErrChecksumMismatch  = errors.New("applied migration checksum does not match file")
+	ErrNothingToRollback = errors.New("no applied migrations to roll back")
 )
 
-func Up(cfg *config.Config) error {
-	db, err := gorm.Open(postgres.Open(cfg.PGdb.DSN), &gorm.Config{})
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Dirty     bool       `json:"checksum_mismatch,omitempty"`
+}
+
+type appliedMigration struct {
+	Version   int64
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []*Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(files)
 	if err != nil {
-		return fmt.Errorf("could not connect to database: %w", err)
+		return nil, err
 	}
 
-	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error; err != nil {
-		return fmt.Errorf("failed creating extension \"uuid-ossp\": %w", err)
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up применяет все неприменённые миграции и возвращает их
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	var applied []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down откатывает steps последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	var rolledBack []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		rolledBack, err = m.down(ctx, conn, steps)
+		return err
+	})
+	return rolledBack, err
+}
+
+// Redo откатывает и заново применяет последнюю миграцию
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var migration *Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		rolledBack, err := m.down(ctx, conn, 1)
+		if err != nil {
+			return err
+		}
+		migration = rolledBack[0]
+		return m.apply(ctx, conn, migration)
+	})
+	return migration, err
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer conn.Close()
 
-	err = initMigrations(db)
+	if err := ensureSchemaTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	done, err := m.applied(ctx, conn)
 	if err != nil {
-		return fmt.Errorf("failed initialising migrations: %w", err)
+		return nil, err
 	}
-	return nil
+
+	statuses := make([]*Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		status := &Status{
+			Version: migration.Version,
+			Name:    migration.Name,
+		}
+		if applied, ok := done[migration.Version]; ok {
+			appliedAt := applied.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.Dirty = applied.Checksum != migration.Checksum
+		}
+		statuses[i] = status
+	}
+	return statuses, nil
 }
 
-func initMigrations(db *gorm.DB) error {
-	err := db.AutoMigrate(&models.Organization{})
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, steps int) ([]*Migration, error) {
+	done, err := m.applied(ctx, conn)
 	if err != nil {
-		err := db.Migrator().DropTable(&models.Organization{})
-		if err != nil {
-			return fmt.Errorf("failed dropping table organizations: %w", err)
+		return nil, err
+	}
+	if err := m.verify(done); err != nil {
+		return nil, err
+	}
+
+	var rolledBack []*Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := done[migration.Version]; !ok {
+			continue
+		}
+		if err := m.rollback(ctx, conn, migration); err != nil {
+			return rolledBack, err
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+	if len(rolledBack) == 0 {
+		return nil, ErrNothingToRollback
+	}
+	return rolledBack, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("failed applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, now())",
+			migration.Version, migration.Name, migration.Checksum,
+		)
+		return err
+	})
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return fmt.Errorf("failed rolling back migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+}
+
+// verify проверяет, что применённые миграции не были изменены после применения
+func (m *Migrator) verify(done map[int64]appliedMigration) error {
+	for _, migration := range m.migrations {
+		if applied, ok := done[migration.Version]; ok && applied.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, migration.Version, migration.Name)
 		}
-		return fmt.Errorf("failed creating table organizations: %w", err)
 	}
+	return nil
+}
 
-	err = db.AutoMigrate(&models.LoanApplication{})
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum, applied_at FROM schema_migrations")
 	if err != nil {
-		err := db.Migrator().DropTable(&models.LoanApplication{})
-		if err != nil {
-			return fmt.Errorf("failed dropping table loan_applications: %w", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var applied appliedMigration
+		if err := rows.Scan(&applied.Version, &applied.Checksum, &applied.AppliedAt); err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("failed creating table loan_applications: %w", err)
+		done[applied.Version] = applied
 	}
+	return done, rows.Err()
+}
 
-	err = db.AutoMigrate(&models.Settings{})
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
 	if err != nil {
-		err := db.Migrator().DropTable(&models.Settings{})
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("failed acquiring migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	if err := ensureSchemaTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureSchemaTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			checksum   CHAR(64) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed creating table schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func loadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
 		if err != nil {
-			return fmt.Errorf("failed dropping table settings: %w", err)
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s, %s", version, migration.Name, match[2])
+		}
+
+		switch match[3] {
+		case "up":
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		case "down":
+			migration.Down = string(content)
 		}
-		return fmt.Errorf("failed creating table settings: %w", err)
 	}
-	return nil
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
 }
@@ -0,0 +1,118 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(content)}
+	}
+
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions []int64
+		wantErr  bool
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"sql/0010_later.up.sql":   file("up 10"),
+				"sql/0010_later.down.sql": file("down 10"),
+				"sql/0002_first.up.sql":   file("up 2"),
+				"sql/0002_first.down.sql": file("down 2"),
+			},
+			versions: []int64{2, 10},
+		},
+		{
+			name: "missing down file",
+			fsys: fstest.MapFS{
+				"sql/0001_init.up.sql": file("up"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty down file",
+			fsys: fstest.MapFS{
+				"sql/0001_init.up.sql":   file("up"),
+				"sql/0001_init.down.sql": file(""),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid file name",
+			fsys: fstest.MapFS{
+				"sql/init.sql": file("up"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "different names for one version",
+			fsys: fstest.MapFS{
+				"sql/0001_init.up.sql":    file("up"),
+				"sql/0001_other.down.sql": file("down"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadMigrations(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.versions) {
+				t.Fatalf("loadMigrations() returned %d migrations, want %d", len(got), len(tt.versions))
+			}
+			for i, version := range tt.versions {
+				if got[i].Version != version {
+					t.Errorf("migration[%d].Version = %d, want %d", i, got[i].Version, version)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrationsChecksum(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_init.up.sql":   &fstest.MapFile{Data: []byte("create table t ();")},
+		"sql/0001_init.down.sql": &fstest.MapFile{Data: []byte("drop table t;")},
+	}
+	first, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	fsys["sql/0001_init.down.sql"] = &fstest.MapFile{Data: []byte("drop table if exists t;")}
+	sameUp, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if first[0].Checksum != sameUp[0].Checksum {
+		t.Error("Checksum changed after editing the down file")
+	}
+
+	fsys["sql/0001_init.up.sql"] = &fstest.MapFile{Data: []byte("create table t (id int);")}
+	changedUp, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if first[0].Checksum == changedUp[0].Checksum {
+		t.Error("Checksum did not change after editing the up file")
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(files)
+	if err != nil {
+		t.Fatalf("loadMigrations(embedded) error = %v", err)
+	}
+	for i, migration := range migrations {
+		if want := int64(i + 1); migration.Version != want {
+			t.Errorf("migration %s has version %d, want %d without gaps", migration.Name, migration.Version, want)
+		}
+	}
+}
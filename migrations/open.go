@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"app_aggregator/internal/config"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Open открывает соединение с Postgres для применения миграций
+func Open(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("pgx", cfg.PGdb.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	return db, nil
+}
+
+// Up применяет все неприменённые миграции
+func Up(ctx context.Context, cfg *config.Config) ([]*Migration, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Up(ctx)
+}
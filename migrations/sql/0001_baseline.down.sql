@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS settings;
+DROP TABLE IF EXISTS loan_applications;
+DROP TABLE IF EXISTS organizations;
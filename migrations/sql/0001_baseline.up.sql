@@ -0,0 +1,52 @@
+-- Базовая схема. Таблицы могли быть созданы ранее через AutoMigrate, поэтому IF NOT EXISTS.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    uuid       UUID DEFAULT uuid_generate_v4(),
+    name       VARCHAR(255) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_organizations_deleted_at ON organizations (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_uuid ON organizations (uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_name ON organizations (name);
+
+CREATE TABLE IF NOT EXISTS loan_applications (
+    id                         BIGSERIAL PRIMARY KEY,
+    created_at                 TIMESTAMPTZ,
+    updated_at                 TIMESTAMPTZ,
+    deleted_at                 TIMESTAMPTZ,
+    uuid                       UUID NOT NULL DEFAULT uuid_generate_v4(),
+    incoming_organization_uuid UUID NOT NULL,
+    issue_organization_uuid    UUID NOT NULL,
+    value                      BIGINT NOT NULL,
+    phone                      VARCHAR(20) NOT NULL,
+    comment                    TEXT,
+    CONSTRAINT chk_loan_applications_value CHECK (value >= 1000),
+    CONSTRAINT fk_loan_applications_incoming_organization
+        FOREIGN KEY (incoming_organization_uuid) REFERENCES organizations (uuid),
+    CONSTRAINT fk_loan_applications_issue_organization
+        FOREIGN KEY (issue_organization_uuid) REFERENCES organizations (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_deleted_at ON loan_applications (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_incoming_organization_uuid ON loan_applications (incoming_organization_uuid);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_issue_organization_uuid ON loan_applications (issue_organization_uuid);
+
+CREATE TABLE IF NOT EXISTS settings (
+    id                BIGSERIAL PRIMARY KEY,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ,
+    deleted_at        TIMESTAMPTZ,
+    uuid              UUID DEFAULT uuid_generate_v4(),
+    organisation_uuid UUID NOT NULL,
+    new_client        BOOLEAN DEFAULT false,
+    pdn               BIGINT DEFAULT 0,
+    has_debt          BOOLEAN DEFAULT false,
+    CONSTRAINT chk_settings_pdn CHECK (pdn >= 0 AND pdn <= 80),
+    CONSTRAINT fk_settings_organization
+        FOREIGN KEY (organisation_uuid) REFERENCES organizations (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_settings_deleted_at ON settings (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_settings_organisation_uuid ON settings (organisation_uuid);
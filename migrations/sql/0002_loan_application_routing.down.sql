@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_loan_applications_phone;
+DROP INDEX IF EXISTS idx_loan_applications_value_id;
+DROP INDEX IF EXISTS idx_loan_applications_created_at_id;
+DROP INDEX IF EXISTS idx_loan_applications_status;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS status;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS unknown_sources;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS routing_reason;
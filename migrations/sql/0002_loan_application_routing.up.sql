@@ -0,0 +1,7 @@
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS routing_reason TEXT;
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS unknown_sources TEXT;
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'new';
+CREATE INDEX IF NOT EXISTS idx_loan_applications_status ON loan_applications (status);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_created_at_id ON loan_applications (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_value_id ON loan_applications (value, id);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_phone ON loan_applications (phone);
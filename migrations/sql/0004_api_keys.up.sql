@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id                BIGSERIAL PRIMARY KEY,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ,
+    deleted_at        TIMESTAMPTZ,
+    uuid              UUID DEFAULT uuid_generate_v4(),
+    organization_uuid UUID NOT NULL,
+    name              VARCHAR(255) NOT NULL,
+    prefix            VARCHAR(16) NOT NULL,
+    key_hash          CHAR(64) NOT NULL,
+    last_used_at      TIMESTAMPTZ,
+    revoked_at        TIMESTAMPTZ,
+    CONSTRAINT fk_api_keys_organization
+        FOREIGN KEY (organization_uuid) REFERENCES organizations (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_uuid ON api_keys (uuid);
+CREATE INDEX IF NOT EXISTS idx_api_keys_organization_uuid ON api_keys (organization_uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS operator_tokens;
+DROP TABLE IF EXISTS operators;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS operators (
+    id            BIGSERIAL PRIMARY KEY,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    deleted_at    TIMESTAMPTZ,
+    uuid          UUID DEFAULT uuid_generate_v4(),
+    login         VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(100) NOT NULL,
+    role          VARCHAR(20) NOT NULL,
+    disabled      BOOLEAN NOT NULL DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS idx_operators_deleted_at ON operators (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_operators_uuid ON operators (uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_operators_login ON operators (login);
+
+CREATE TABLE IF NOT EXISTS operator_tokens (
+    id            BIGSERIAL PRIMARY KEY,
+    operator_uuid UUID NOT NULL,
+    token_hash    CHAR(64) NOT NULL,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    revoked_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ,
+    CONSTRAINT fk_operator_tokens_operator
+        FOREIGN KEY (operator_uuid) REFERENCES operators (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_operator_tokens_operator_uuid ON operator_tokens (operator_uuid);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_operator_tokens_token_hash ON operator_tokens (token_hash);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id                BIGSERIAL PRIMARY KEY,
+    organization_uuid UUID NOT NULL,
+    key               VARCHAR(255) NOT NULL,
+    request_hash      CHAR(64) NOT NULL,
+    status_code       INTEGER NOT NULL DEFAULT 0,
+    response_body     BYTEA,
+    created_at        TIMESTAMPTZ NOT NULL,
+    expires_at        TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_idempotency_keys_organization
+        FOREIGN KEY (organization_uuid) REFERENCES organizations (uuid) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_keys_organization_key ON idempotency_keys (organization_uuid, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
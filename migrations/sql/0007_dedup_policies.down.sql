@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS loan_application_duplicates;
+DROP INDEX IF EXISTS idx_loan_applications_phone_created_at;
+DROP INDEX IF EXISTS idx_loan_applications_duplicate_of_uuid;
+ALTER TABLE loan_applications DROP CONSTRAINT IF EXISTS fk_loan_applications_duplicate_of;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS duplicate_of_uuid;
+DROP INDEX IF EXISTS idx_loan_applications_uuid;
+DROP TABLE IF EXISTS dedup_policies;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS dedup_policies (
+    id                BIGSERIAL PRIMARY KEY,
+    organization_uuid UUID NOT NULL,
+    window_hours      INTEGER NOT NULL,
+    scope             VARCHAR(30) NOT NULL,
+    action            VARCHAR(20) NOT NULL,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ,
+    CONSTRAINT fk_dedup_policies_organization
+        FOREIGN KEY (organization_uuid) REFERENCES organizations (uuid) ON DELETE CASCADE,
+    CONSTRAINT chk_dedup_policies_window_hours CHECK (window_hours > 0),
+    CONSTRAINT chk_dedup_policies_scope CHECK (scope IN ('incoming_organization', 'global')),
+    CONSTRAINT chk_dedup_policies_action CHECK (action IN ('reject', 'merge', 'link'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dedup_policies_organization_uuid ON dedup_policies (organization_uuid);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loan_applications_uuid ON loan_applications (uuid);
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS duplicate_of_uuid UUID;
+ALTER TABLE loan_applications ADD CONSTRAINT fk_loan_applications_duplicate_of
+    FOREIGN KEY (duplicate_of_uuid) REFERENCES loan_applications (uuid) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_loan_applications_duplicate_of_uuid ON loan_applications (duplicate_of_uuid);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_phone_created_at ON loan_applications (phone, created_at);
+
+CREATE TABLE IF NOT EXISTS loan_application_duplicates (
+    id                         BIGSERIAL PRIMARY KEY,
+    original_uuid              UUID NOT NULL,
+    duplicate_uuid             UUID,
+    incoming_organization_uuid UUID NOT NULL,
+    action                     VARCHAR(20) NOT NULL,
+    created_at                 TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_loan_application_duplicates_original
+        FOREIGN KEY (original_uuid) REFERENCES loan_applications (uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_loan_application_duplicates_duplicate
+        FOREIGN KEY (duplicate_uuid) REFERENCES loan_applications (uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_loan_application_duplicates_incoming_organization
+        FOREIGN KEY (incoming_organization_uuid) REFERENCES organizations (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_loan_application_duplicates_original_uuid ON loan_application_duplicates (original_uuid, created_at);
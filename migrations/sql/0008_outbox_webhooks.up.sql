@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id                BIGSERIAL PRIMARY KEY,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ,
+    deleted_at        TIMESTAMPTZ,
+    uuid              UUID NOT NULL DEFAULT uuid_generate_v4(),
+    organization_uuid UUID NOT NULL,
+    url               TEXT NOT NULL,
+    secret            VARCHAR(64) NOT NULL,
+    event_types       TEXT,
+    CONSTRAINT fk_webhook_endpoints_organization
+        FOREIGN KEY (organization_uuid) REFERENCES organizations (uuid)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_deleted_at ON webhook_endpoints (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_endpoints_uuid ON webhook_endpoints (uuid);
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_organization_uuid ON webhook_endpoints (organization_uuid);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id          BIGSERIAL PRIMARY KEY,
+    uuid        UUID NOT NULL DEFAULT uuid_generate_v4(),
+    event_type  VARCHAR(50) NOT NULL,
+    entity_uuid UUID NOT NULL,
+    payload     JSONB NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_uuid ON outbox_events (uuid);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_entity_uuid ON outbox_events (entity_uuid);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               BIGSERIAL PRIMARY KEY,
+    uuid             UUID NOT NULL DEFAULT uuid_generate_v4(),
+    event_id         BIGINT NOT NULL,
+    endpoint_id      BIGINT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL,
+    last_status_code INTEGER,
+    last_error       TEXT,
+    delivered_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_webhook_deliveries_event
+        FOREIGN KEY (event_id) REFERENCES outbox_events (id) ON DELETE CASCADE,
+    CONSTRAINT fk_webhook_deliveries_endpoint
+        FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_uuid ON webhook_deliveries (uuid);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
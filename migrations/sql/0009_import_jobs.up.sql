@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id                BIGSERIAL PRIMARY KEY,
+    uuid              UUID NOT NULL DEFAULT uuid_generate_v4(),
+    organization_uuid UUID NOT NULL,
+    file_name         TEXT NOT NULL,
+    status            VARCHAR(20) NOT NULL,
+    total_rows        INTEGER NOT NULL,
+    processed_rows    INTEGER NOT NULL DEFAULT 0,
+    created           INTEGER NOT NULL DEFAULT 0,
+    duplicates        INTEGER NOT NULL DEFAULT 0,
+    invalid           INTEGER NOT NULL DEFAULT 0,
+    failed            INTEGER NOT NULL DEFAULT 0,
+    error             TEXT,
+    actor             VARCHAR(100) NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL,
+    updated_at        TIMESTAMPTZ NOT NULL,
+    finished_at       TIMESTAMPTZ,
+    CONSTRAINT fk_import_jobs_organization
+        FOREIGN KEY (organization_uuid) REFERENCES organizations (uuid),
+    CONSTRAINT chk_import_jobs_status CHECK (status IN ('running', 'completed', 'failed'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_jobs_uuid ON import_jobs (uuid);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_organization_uuid ON import_jobs (organization_uuid);
+
+CREATE TABLE IF NOT EXISTS import_job_rows (
+    id                    BIGSERIAL PRIMARY KEY,
+    job_id                BIGINT NOT NULL,
+    line                  INTEGER NOT NULL,
+    status                VARCHAR(20) NOT NULL,
+    reason                TEXT,
+    loan_application_uuid UUID,
+    CONSTRAINT fk_import_job_rows_job
+        FOREIGN KEY (job_id) REFERENCES import_jobs (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_job_rows_job_line ON import_job_rows (job_id, line);
@@ -0,0 +1,3 @@
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS settings_version;
+DROP TABLE IF EXISTS settings_versions;
+ALTER TABLE settings DROP COLUMN IF EXISTS version;
@@ -0,0 +1,21 @@
+ALTER TABLE settings ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS settings_versions (
+    id                BIGSERIAL PRIMARY KEY,
+    organisation_uuid UUID NOT NULL,
+    version           INTEGER NOT NULL,
+    new_client        BOOLEAN NOT NULL,
+    pdn               BIGINT NOT NULL,
+    has_debt          BOOLEAN NOT NULL,
+    actor             VARCHAR(100) NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settings_versions_organisation_version ON settings_versions (organisation_uuid, version);
+
+INSERT INTO settings_versions (organisation_uuid, version, new_client, pdn, has_debt, actor, created_at)
+SELECT organisation_uuid, version, COALESCE(new_client, false), COALESCE(pdn, 0), COALESCE(has_debt, false), 'system', COALESCE(updated_at, now())
+FROM settings
+WHERE deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS settings_version INTEGER;
@@ -0,0 +1,5 @@
+UPDATE loan_applications SET phone = ltrim(phone, '+') WHERE phone LIKE '+%';
+
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS phone_type;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS phone_country;
+ALTER TABLE organizations DROP COLUMN IF EXISTS allowed_phone_countries;
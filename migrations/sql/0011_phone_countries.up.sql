@@ -0,0 +1,9 @@
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS allowed_phone_countries TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS phone_country VARCHAR(2);
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS phone_type VARCHAR(10);
+
+-- Раньше принимались только российские мобильные номера и хранились без +
+UPDATE loan_applications
+SET phone = '+' || phone, phone_country = 'RU', phone_type = 'mobile'
+WHERE phone ~ '^79[0-9]{9}$';
@@ -0,0 +1,11 @@
+-- Без ключей приложения зашифрованные телефоны не восстановить, поэтому откат возможен только до шифрования
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM loan_applications WHERE phone_encrypted IS NOT NULL) THEN
+        RAISE EXCEPTION 'loan_applications contain encrypted phones, decrypt them before rolling back';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_loan_applications_phone_hash;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS phone_hash;
+ALTER TABLE loan_applications DROP COLUMN IF EXISTS phone_encrypted;
@@ -0,0 +1,5 @@
+-- Телефоны шифруются приложением: существующие заявки перешифровываются при запуске API
+-- или командой aggregatorctl phones encrypt, после чего колонка phone остается пустой
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS phone_encrypted TEXT;
+ALTER TABLE loan_applications ADD COLUMN IF NOT EXISTS phone_hash VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_loan_applications_phone_hash ON loan_applications (phone_hash);
@@ -0,0 +1 @@
+-- Маскирование необратимо, откат ничего не меняет
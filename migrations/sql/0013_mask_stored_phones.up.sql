@@ -0,0 +1,54 @@
+-- Журналы, записанные до шифрования телефонов, содержат их в открытом виде.
+-- Телефоны в них маскируются так же, как validators.MaskPhone: +79123454567 -> +7912***4567
+CREATE OR REPLACE FUNCTION mask_phone(phone TEXT) RETURNS TEXT AS $$
+    SELECT CASE
+        WHEN phone IS NULL OR phone LIKE '%*%' THEN phone
+        WHEN phone LIKE '+%' AND length(phone) < 9 THEN '+' || repeat('*', length(phone) - 1)
+        WHEN phone LIKE '+%' THEN '+' || substr(phone, 2, 4) || '***' || right(phone, 4)
+        WHEN length(phone) < 8 THEN repeat('*', length(phone))
+        ELSE left(phone, 4) || '***' || right(phone, 4)
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+-- mask_phone_at маскирует строку по пути path в документе, остальные значения не меняются
+CREATE OR REPLACE FUNCTION mask_phone_at(doc JSONB, path TEXT[]) RETURNS JSONB AS $$
+    SELECT CASE
+        WHEN jsonb_typeof(doc #> path) = 'string' THEN jsonb_set(doc, path, to_jsonb(mask_phone(doc #>> path)))
+        ELSE doc
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE audit_logs DISABLE TRIGGER audit_logs_append_only;
+UPDATE audit_logs
+SET before = mask_phone_at(before, '{phone}'),
+    after  = mask_phone_at(after, '{phone}'),
+    diff   = mask_phone_at(mask_phone_at(diff, '{phone,from}'), '{phone,to}')
+WHERE entity_type = 'loan_application';
+ALTER TABLE audit_logs ENABLE TRIGGER audit_logs_append_only;
+
+UPDATE outbox_events
+SET payload = mask_phone_at(payload, '{data,phone}')
+WHERE payload #>> '{data,phone}' NOT LIKE '%*%';
+
+-- Сохраненные ответы - JSON заявки, остальные тела не трогаются
+DO $$
+DECLARE
+    rec RECORD;
+    body JSONB;
+BEGIN
+    FOR rec IN SELECT id, response_body FROM idempotency_keys WHERE response_body IS NOT NULL LOOP
+        BEGIN
+            body := convert_from(rec.response_body, 'UTF8')::jsonb;
+        EXCEPTION WHEN others THEN
+            CONTINUE;
+        END;
+        IF jsonb_typeof(body) = 'object' AND jsonb_typeof(body -> 'phone') = 'string' THEN
+            UPDATE idempotency_keys
+            SET response_body = convert_to(mask_phone_at(body, '{phone}')::text, 'UTF8')
+            WHERE id = rec.id;
+        END IF;
+    END LOOP;
+END $$;
+
+DROP FUNCTION mask_phone_at(JSONB, TEXT[]);
+DROP FUNCTION mask_phone(TEXT);
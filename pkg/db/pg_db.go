@@ -2,18 +2,31 @@ package db
 
 import (
 	"app_aggregator/internal/config"
+	"app_aggregator/internal/tracing"
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlserver"
 	"gorm.io/gorm"
 )
 
+// PGSourceName - имя основной базы в трейсах и метриках
+const PGSourceName = "pg"
+
+type ClientSourceDB struct {
+	Name             string
+	OrganizationUUID uuid.UUID
+	Timeout          time.Duration
+	DB               *gorm.DB
+}
+
 type DB struct {
-	PGDB      *gorm.DB
-	KassaDB   *gorm.DB
-	DoverixDB *gorm.DB
-	DEDB      *gorm.DB
+	PGDB          *gorm.DB
+	ClientSources []*ClientSourceDB
 }
 
 func InitDB(cfg *config.Config) (*DB, error) {
@@ -21,24 +34,30 @@ func InitDB(cfg *config.Config) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	kassaDb, err := gorm.Open(sqlserver.Open(cfg.SQL.DsnKassa))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to kassa database: %w", err)
-	}
-	doverixDb, err := gorm.Open(sqlserver.Open(cfg.SQL.DsnDoverix))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to doverix database: %w", err)
+	if err := db.Use(tracing.NewGormPlugin(PGSourceName, semconv.DBSystemPostgreSQL)); err != nil {
+		return nil, fmt.Errorf("failed to register tracing for database: %w", err)
 	}
-	deDb, err := gorm.Open(sqlserver.Open(cfg.SQL.DsnDe))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to dened database: %w", err)
+
+	clientSources := make([]*ClientSourceDB, 0, len(cfg.ClientSources))
+	for _, source := range cfg.ClientSources {
+		sourceDb, err := gorm.Open(sqlserver.Open(source.DSN))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s database: %w", source.Name, err)
+		}
+		if err := sourceDb.Use(tracing.NewGormPlugin(source.Name, semconv.DBSystemMSSQL)); err != nil {
+			return nil, fmt.Errorf("failed to register tracing for %s database: %w", source.Name, err)
+		}
+		clientSources = append(clientSources, &ClientSourceDB{
+			Name:             source.Name,
+			OrganizationUUID: source.OrganizationUUID,
+			Timeout:          source.Timeout,
+			DB:               sourceDb,
+		})
 	}
 
 	return &DB{
-		PGDB:      db,
-		KassaDB:   kassaDb,
-		DoverixDB: doverixDb,
-		DEDB:      deDb,
+		PGDB:          db,
+		ClientSources: clientSources,
 	}, nil
 }
 
@@ -53,26 +72,13 @@ func (db *DB) Close() error {
 		}
 	}
 
-	if db.KassaDB != nil {
-		if sqlDB, err := db.KassaDB.DB(); err == nil {
-			if err := sqlDB.Close(); err != nil {
-				errors = append(errors, fmt.Errorf("failed to close KassaDB: %w", err))
-			}
+	for _, source := range db.ClientSources {
+		if source.DB == nil {
+			continue
 		}
-	}
-
-	if db.DoverixDB != nil {
-		if sqlDB, err := db.DoverixDB.DB(); err == nil {
+		if sqlDB, err := source.DB.DB(); err == nil {
 			if err := sqlDB.Close(); err != nil {
-				errors = append(errors, fmt.Errorf("failed to close DoverixDB: %w", err))
-			}
-		}
-	}
-
-	if db.DEDB != nil {
-		if sqlDB, err := db.DEDB.DB(); err == nil {
-			if err := sqlDB.Close(); err != nil {
-				errors = append(errors, fmt.Errorf("failed to close DEDB: %w", err))
+				errors = append(errors, fmt.Errorf("failed to close %s database: %w", source.Name, err))
 			}
 		}
 	}
@@ -83,3 +89,12 @@ func (db *DB) Close() error {
 
 	return nil
 }
+
+// Ping проверяет соединение с базой
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -3,34 +3,76 @@ package validators
 import (
 	"app_aggregator/internal"
 	"regexp"
+	"slices"
 	"strings"
 )
 
-func ValidPhone(phoneNumber string) bool {
-	cleanedPhone := regexp.MustCompile(`\D`).ReplaceAllString(phoneNumber, "")
-
-	pattern := `^(?:\+7|8|7)\s?9\d{2}\s?\d{3}\s?\d{2}\s?\d{2}$`
-	re := regexp.MustCompile(pattern)
+var nonDigitsRe = regexp.MustCompile(`\D`)
 
-	return re.MatchString(cleanedPhone)
+// Phone - номер в формате E.164 со страной и типом
+type Phone struct {
+	E164    string
+	Country string
+	Type    PhoneType
 }
 
-func PhoneNormalization(phoneNumber string) (string, error) {
-	re := regexp.MustCompile(`\D`)
-	digits := re.ReplaceAllString(phoneNumber, "")
-
+// ParsePhone разбирает номер в международном (+7..., 00375...) или национальном (8..., 80...) формате.
+// Если allowed не пуст, номер должен принадлежать одной из перечисленных стран.
+// Номер, который подходит под правила нескольких стран, считается неверным.
+func ParsePhone(phoneNumber string, allowed ...string) (*Phone, error) {
+	phoneNumber = strings.TrimSpace(phoneNumber)
+	international := strings.HasPrefix(phoneNumber, "+")
+	digits := nonDigitsRe.ReplaceAllString(phoneNumber, "")
 	if digits == "" {
-		return "", internal.ErrEmptyPhoneNumber
+		return nil, internal.ErrEmptyPhoneNumber
+	}
+	if !international && strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+		international = true
 	}
-	switch {
-	case strings.HasPrefix(digits, "7") && len(digits) == 11:
-		return digits, nil
-	case strings.HasPrefix(digits, "8") && len(digits) == 11:
-		return "7" + digits[1:], nil
-	case strings.HasPrefix(digits, "9") && len(digits) == 10:
-		return "7" + digits, nil
+
+	var found *Phone
+	for _, rule := range phoneCountryRules() {
+		phone := rule.match(digits, international)
+		if phone == nil {
+			continue
+		}
+		if found != nil {
+			return nil, internal.ErrInvalidPhoneNumber
+		}
+		found = phone
+	}
+	if found == nil {
+		return nil, internal.ErrInvalidPhoneNumber
+	}
+
+	if len(allowed) > 0 && !slices.Contains(allowed, found.Country) {
+		return nil, internal.ErrPhoneCountryNotAllowed
 	}
+	return found, nil
+}
 
-	return "", internal.ErrInvalidPhoneNumber
+func ValidPhone(phoneNumber string) bool {
+	_, err := ParsePhone(phoneNumber)
+	return err == nil
+}
 
+// PhoneNormalization приводит номер к формату E.164: 8 (912) 345-67-89 -> +79123456789
+func PhoneNormalization(phoneNumber string) (string, error) {
+	phone, err := ParsePhone(phoneNumber)
+	if err != nil {
+		return "", err
+	}
+	return phone.E164, nil
+}
+
+// MaskPhone скрывает середину номера: +79123454567 -> +7912***4567
+func MaskPhone(phone string) string {
+	if strings.HasPrefix(phone, "+") {
+		return "+" + MaskPhone(phone[1:])
+	}
+	if len(phone) < 8 {
+		return strings.Repeat("*", len(phone))
+	}
+	return phone[:4] + "***" + phone[len(phone)-4:]
 }
@@ -0,0 +1,134 @@
+package validators
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+type PhoneType string
+
+const (
+	PhoneTypeMobile   PhoneType = "mobile"
+	PhoneTypeLandline PhoneType = "landline"
+)
+
+// PhoneCountryRule описывает номера одной страны: код страны, длину национального номера,
+// префикс для набора внутри страны и коды мобильных и стационарных номеров
+type PhoneCountryRule struct {
+	Country        string
+	CallingCode    string
+	NationalLength int
+	TrunkPrefix    string
+	Mobile         []string
+	Landline       []string
+}
+
+var phoneCountries = struct {
+	sync.RWMutex
+	rules []PhoneCountryRule
+}{}
+
+func init() {
+	RegisterPhoneCountry(PhoneCountryRule{
+		Country:        "RU",
+		CallingCode:    "7",
+		NationalLength: 10,
+		TrunkPrefix:    "8",
+		Mobile:         []string{"9"},
+		Landline:       []string{"3", "4", "8"},
+	})
+	RegisterPhoneCountry(PhoneCountryRule{
+		Country:        "KZ",
+		CallingCode:    "7",
+		NationalLength: 10,
+		TrunkPrefix:    "8",
+		Mobile:         []string{"700", "701", "702", "705", "706", "707", "708", "747", "771", "775", "776", "777", "778"},
+		Landline:       []string{"71", "72"},
+	})
+	RegisterPhoneCountry(PhoneCountryRule{
+		Country:        "BY",
+		CallingCode:    "375",
+		NationalLength: 9,
+		TrunkPrefix:    "80",
+		Mobile:         []string{"25", "29", "33", "44"},
+		Landline:       []string{"15", "16", "17", "21", "22", "23"},
+	})
+	RegisterPhoneCountry(PhoneCountryRule{
+		Country:        "UZ",
+		CallingCode:    "998",
+		NationalLength: 9,
+		Mobile:         []string{"20", "33", "50", "77", "88", "90", "91", "93", "94", "95", "97", "98", "99"},
+		Landline:       []string{"55", "61", "62", "65", "66", "67", "69", "70", "71", "72", "73", "74", "75", "76", "79"},
+	})
+}
+
+// RegisterPhoneCountry добавляет правила страны или заменяет уже зарегистрированные
+func RegisterPhoneCountry(rule PhoneCountryRule) {
+	phoneCountries.Lock()
+	defer phoneCountries.Unlock()
+
+	index := slices.IndexFunc(phoneCountries.rules, func(r PhoneCountryRule) bool { return r.Country == rule.Country })
+	if index >= 0 {
+		phoneCountries.rules[index] = rule
+		return
+	}
+	phoneCountries.rules = append(phoneCountries.rules, rule)
+}
+
+// PhoneCountries возвращает коды стран (ISO 3166-1 alpha-2), для которых есть правила
+func PhoneCountries() []string {
+	rules := phoneCountryRules()
+	countries := make([]string, len(rules))
+	for i, rule := range rules {
+		countries[i] = rule.Country
+	}
+	slices.Sort(countries)
+	return countries
+}
+
+func SupportedPhoneCountry(country string) bool {
+	return slices.Contains(PhoneCountries(), country)
+}
+
+func phoneCountryRules() []PhoneCountryRule {
+	phoneCountries.RLock()
+	defer phoneCountries.RUnlock()
+	return slices.Clone(phoneCountries.rules)
+}
+
+// match проверяет цифры номера в международном формате, а для номеров без + еще и в национальном
+func (r PhoneCountryRule) match(digits string, international bool) *Phone {
+	candidates := []string{r.CallingCode}
+	if !international {
+		if r.TrunkPrefix != "" {
+			candidates = append(candidates, r.TrunkPrefix)
+		}
+		candidates = append(candidates, "")
+	}
+
+	for _, prefix := range candidates {
+		if !strings.HasPrefix(digits, prefix) || len(digits) != len(prefix)+r.NationalLength {
+			continue
+		}
+		national := digits[len(prefix):]
+		if phoneType, ok := r.phoneType(national); ok {
+			return &Phone{E164: "+" + r.CallingCode + national, Country: r.Country, Type: phoneType}
+		}
+	}
+	return nil
+}
+
+func (r PhoneCountryRule) phoneType(national string) (PhoneType, bool) {
+	for _, code := range r.Mobile {
+		if strings.HasPrefix(national, code) {
+			return PhoneTypeMobile, true
+		}
+	}
+	for _, code := range r.Landline {
+		if strings.HasPrefix(national, code) {
+			return PhoneTypeLandline, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package validators
+
+import (
+	"errors"
+	"testing"
+
+	"app_aggregator/internal"
+)
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		allowed     []string
+		wantE164    string
+		wantCountry string
+		wantType    PhoneType
+		wantErr     error
+	}{
+		{"RU mobile international", "+79123456789", nil, "+79123456789", "RU", PhoneTypeMobile, nil},
+		{"RU mobile with trunk prefix and formatting", "8 (912) 345-67-89", nil, "+79123456789", "RU", PhoneTypeMobile, nil},
+		{"RU mobile without prefix", "9123456789", nil, "+79123456789", "RU", PhoneTypeMobile, nil},
+		{"RU landline", "+7 495 123-45-67", nil, "+74951234567", "RU", PhoneTypeLandline, nil},
+		{"KZ mobile", "+77011234567", nil, "+77011234567", "KZ", PhoneTypeMobile, nil},
+		{"KZ mobile with trunk prefix", "87011234567", nil, "+77011234567", "KZ", PhoneTypeMobile, nil},
+		{"KZ landline", "+77172123456", nil, "+77172123456", "KZ", PhoneTypeLandline, nil},
+		{"BY mobile", "+375291234567", nil, "+375291234567", "BY", PhoneTypeMobile, nil},
+		{"BY mobile with 00 prefix", "00375291234567", nil, "+375291234567", "BY", PhoneTypeMobile, nil},
+		{"BY mobile with trunk prefix", "80291234567", nil, "+375291234567", "BY", PhoneTypeMobile, nil},
+		{"UZ mobile", "+998901234567", nil, "+998901234567", "UZ", PhoneTypeMobile, nil},
+		{"allowed country", "+79123456789", []string{"KZ", "RU"}, "+79123456789", "RU", PhoneTypeMobile, nil},
+		{"country not allowed", "+375291234567", []string{"RU"}, "", "", "", internal.ErrPhoneCountryNotAllowed},
+		{"ambiguous BY and UZ national number", "331234567", nil, "", "", "", internal.ErrInvalidPhoneNumber},
+		{"national number with plus", "+9123456789", nil, "", "", "", internal.ErrInvalidPhoneNumber},
+		{"unknown RU code", "+71234567890", nil, "", "", "", internal.ErrInvalidPhoneNumber},
+		{"too short", "+7912", nil, "", "", "", internal.ErrInvalidPhoneNumber},
+		{"too long", "+791234567890", nil, "", "", "", internal.ErrInvalidPhoneNumber},
+		{"empty", "", nil, "", "", "", internal.ErrEmptyPhoneNumber},
+		{"no digits", "phone", nil, "", "", "", internal.ErrEmptyPhoneNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phone, err := ParsePhone(tt.raw, tt.allowed...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParsePhone(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePhone(%q) error = %v", tt.raw, err)
+			}
+			if phone.E164 != tt.wantE164 || phone.Country != tt.wantCountry || phone.Type != tt.wantType {
+				t.Errorf("ParsePhone(%q) = %+v, want %s %s %s", tt.raw, phone, tt.wantE164, tt.wantCountry, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		phone, want string
+	}{
+		{"+79123454567", "+7912***4567"},
+		{"79123454567", "7912***4567"},
+		{"+375291234567", "+3752***4567"},
+		{"1234567", "*******"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MaskPhone(tt.phone); got != tt.want {
+			t.Errorf("MaskPhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
@@ -13,3 +13,15 @@ api_docker_build:
 api_local_run:
 	go run ./cmd/api/app_aggregator_api.go
 
+
+migrate_up:
+	go run ./cmd/migrate up
+
+migrate_down:
+	go run ./cmd/migrate down
+
+migrate_status:
+	go run ./cmd/migrate status
+
+migrate_redo:
+	go run ./cmd/migrate redo